@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gusbicalho/go-lambda/format"
+	"github.com/gusbicalho/go-lambda/keymap"
+	"github.com/gusbicalho/go-lambda/locally_nameless/animate"
+	"github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
+	"github.com/gusbicalho/go-lambda/locally_nameless/diff"
+	ln_expr "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/graph"
+	"github.com/gusbicalho/go-lambda/locally_nameless/latex"
+	ln_pretty "github.com/gusbicalho/go-lambda/locally_nameless/pretty"
+	"github.com/gusbicalho/go-lambda/locally_nameless/stepper"
+	"github.com/gusbicalho/go-lambda/parse_tree_to_locally_nameless"
+	"github.com/gusbicalho/go-lambda/parser"
+	"github.com/gusbicalho/go-lambda/pretty"
+	"github.com/gusbicalho/go-lambda/session"
+	"github.com/gusbicalho/go-lambda/tokenizer"
+	"github.com/gusbicalho/go-lambda/tui"
+)
+
+var commands = map[string]func(args []string) error{
+	"fmt":     fmtCommand,
+	"show":    showCommand,
+	"animate": animateCommand,
+	"trace":   traceCommand,
+	"tui":     tuiCommand,
+	"step":    stepCommand,
+}
+
+func fmtCommand(args []string) error {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	width := flags.Uint("width", 80, "page width")
+	write := flags.Bool("w", false, "write result to (source) file instead of stdout")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: go-lambda fmt [flags] [files]")
+		fmt.Fprintln(flags.Output(), "Sources may define terms with let name = value in body,")
+		fmt.Fprintln(flags.Output(), "so let and in are reserved and can no longer name variables.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		formatted, err := format.Format(os.Stdin, *width)
+		if err != nil {
+			return err
+		}
+		fmt.Print(formatted)
+		return nil
+	}
+
+	for _, path := range flags.Args() {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		formatted, err := format.Format(bytes.NewReader(source), *width)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if !*write {
+			fmt.Print(formatted)
+		} else if formatted != string(source) {
+			if err := os.WriteFile(path, []byte(formatted), 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func showCommand(args []string) error {
+	flags := flag.NewFlagSet("show", flag.ExitOnError)
+	width := flags.Uint("width", 80, "page width")
+	layout := flags.String("layout", "tree", "tree, notation, sexpr, debruijn, dot, mermaid or latex")
+	indexes := flags.Bool("indexes", false, "subscript bound variables with their de Bruijn indexes (latex)")
+	flags.Parse(args)
+
+	source, err := readSource(flags.Args())
+	if err != nil {
+		return err
+	}
+	expr, err := parseSource(source)
+	if err != nil {
+		return err
+	}
+
+	switch *layout {
+	case "dot":
+		return graph.WriteDOT(os.Stdout, expr)
+	case "mermaid":
+		return graph.WriteMermaid(os.Stdout, expr)
+	case "latex":
+		fmt.Println(latex.Term(expr, latex.Options{Indexes: *indexes}))
+		return nil
+	}
+	docLayout, err := ln_pretty.ParseLayout(*layout)
+	if err != nil {
+		return err
+	}
+	doc := ln_pretty.ToLayoutDoc(expr, docLayout, ln_pretty.Options{})
+	if err := doc.Render(os.Stdout, pretty.RenderOptions{Width: *width}); err != nil {
+		return err
+	}
+	fmt.Println()
+	return nil
+}
+
+func animateCommand(args []string) error {
+	flags := flag.NewFlagSet("animate", flag.ExitOnError)
+	strategyName := flags.String("strategy", "normal", "normal or applicative")
+	maxSteps := flags.Uint("steps", 100, "stop after this many steps")
+	output := flags.String("o", "", "write to this file instead of stdout")
+	flags.Parse(args)
+
+	strategy, err := beta_reduce.ParseStrategy(*strategyName)
+	if err != nil {
+		return err
+	}
+	source, err := readSource(flags.Args())
+	if err != nil {
+		return err
+	}
+	expr, err := parseSource(source)
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	steps := beta_reduce.Trace(expr, strategy, *maxSteps)
+	return animate.WriteHTML(out, source, expr, steps)
+}
+
+func traceCommand(args []string) error {
+	flags := flag.NewFlagSet("trace", flag.ExitOnError)
+	strategyName := flags.String("strategy", "normal", "normal or applicative")
+	maxSteps := flags.Uint("steps", 100, "stop after this many steps")
+	format := flags.String("format", "text", "text or latex")
+	indexes := flags.Bool("indexes", false, "subscript bound variables with their de Bruijn indexes (latex)")
+	showDiff := flags.Bool("diff", false, "show what each step changed as a tree diff (text)")
+	color := flags.Bool("color", false, "color the diffs with ANSI escapes (text)")
+	flags.Parse(args)
+
+	strategy, err := beta_reduce.ParseStrategy(*strategyName)
+	if err != nil {
+		return err
+	}
+	source, err := readSource(flags.Args())
+	if err != nil {
+		return err
+	}
+	expr, err := parseSource(source)
+	if err != nil {
+		return err
+	}
+	steps := beta_reduce.Trace(expr, strategy, *maxSteps)
+
+	switch *format {
+	case "text":
+		renderer := pretty.Plain
+		if *color {
+			renderer = ansiRenderer
+		}
+		fmt.Println(ln_expr.ToLambdaNotation(expr, ln_expr.DisplayName))
+		for _, redex := range steps {
+			if *showDiff {
+				doc := diff.OfStep(redex).ToPrettyDoc()
+				if err := doc.Render(os.Stdout, pretty.RenderOptions{Renderer: renderer}); err != nil {
+					return err
+				}
+				fmt.Println()
+			}
+			fmt.Println("→β", ln_expr.ToLambdaNotation(redex.Reduce(), ln_expr.DisplayName))
+		}
+	case "latex":
+		fmt.Print(latex.Derivation(expr, steps, latex.Options{Indexes: *indexes}))
+	default:
+		return errors.New(fmt.Sprint("Unknown format ", *format, ", expected text or latex"))
+	}
+	return nil
+}
+
+func stepCommand(args []string) error {
+	flags := flag.NewFlagSet("step", flag.ExitOnError)
+	strategyName := flags.String("strategy", "normal", "normal or applicative")
+	maxSteps := flags.Uint("steps", 0, "stop after this many steps, or never with 0")
+	color := flags.Bool("color", false, "highlight redexes with ANSI escapes instead of brackets")
+	flags.Parse(args)
+
+	strategy, err := beta_reduce.ParseStrategy(*strategyName)
+	if err != nil {
+		return err
+	}
+	source, err := readSource(flags.Args())
+	if err != nil {
+		return err
+	}
+	expr, err := parseSource(source)
+	if err != nil {
+		return err
+	}
+	options := stepper.Options{Strategy: strategy, MaxSteps: *maxSteps}
+	if *color {
+		options.Renderer = ansiRenderer
+	}
+	return stepper.Write(os.Stdout, expr, options)
+}
+
+func tuiCommand(args []string) error {
+	flags := flag.NewFlagSet("tui", flag.ExitOnError)
+	preset := flags.String("keys", "default", fmt.Sprint("key bindings to start from, one of ", keymap.PresetNames()))
+	keymapPath := flags.String("keymap", "", "keymap file, applied over the preset (default "+defaultKeymapPath()+")")
+	speed := flags.Float64("speed", 2, "steps per second when running the strategy")
+	sessionPath := flags.String("session", "", "resume the session saved in this file, and save it there on quit")
+	flags.Parse(args)
+
+	keys, err := loadKeymap(*preset, *keymapPath)
+	if err != nil {
+		return err
+	}
+	if *speed <= 0 {
+		return errors.New(fmt.Sprint("Speed must be positive, got ", *speed))
+	}
+	saved, err := loadSession(*sessionPath, flags.Args())
+	if err != nil {
+		return err
+	}
+	return runTui(saved, tui.Options{Keys: keys, Speed: *speed, SessionPath: *sessionPath})
+}
+
+// Prints every term the session went through when it ends
+func runTui(saved session.Session, options tui.Options) error {
+	app, err := tui.New(saved, options)
+	if err != nil {
+		return err
+	}
+	if err := app.Run(); err != nil {
+		return err
+	}
+	for _, term := range app.Model().Log() {
+		fmt.Println(term)
+	}
+	return nil
+}
+
+// A new session starts from the source, unless there is one saved in the file
+func loadSession(path string, args []string) (session.Session, error) {
+	if path != "" {
+		file, err := os.Open(path)
+		if err == nil {
+			defer file.Close()
+			if len(args) > 0 {
+				return session.Session{}, errors.New(fmt.Sprint("Session ", path, " already exists, so no source is expected"))
+			}
+			saved, err := session.Read(file)
+			if err != nil {
+				return session.Session{}, fmt.Errorf("%s: %w", path, err)
+			}
+			return saved, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return session.Session{}, err
+		}
+	}
+	source, err := readSource(args)
+	if err != nil {
+		return session.Session{}, err
+	}
+	return session.New(source)
+}
+
+// The keymap file is optional when it is not given explicitly
+func loadKeymap(preset string, path string) (keymap.Keymap, error) {
+	keys, err := keymap.Preset(preset)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		path = defaultKeymapPath()
+		if _, err := os.Stat(path); err != nil {
+			return keys, nil
+		}
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	keys, err = keymap.Load(file, keys)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return keys, nil
+}
+
+func defaultKeymapPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "go-lambda", "keymap")
+}
+
+func parseSource(source string) (ln_expr.Expr, error) {
+	tree, err := parser.Parse(tokenizer.New(strings.NewReader(source)))
+	if err != nil {
+		return nil, err
+	}
+	return parse_tree_to_locally_nameless.ToLocallyNameless(*tree), nil
+}
+
+// The source is given as arguments, or read from stdin when there are none
+func readSource(args []string) (string, error) {
+	if len(args) > 0 {
+		return strings.Join(args, " "), nil
+	}
+	source, err := io.ReadAll(os.Stdin)
+	return string(source), err
+}
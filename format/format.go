@@ -0,0 +1,179 @@
+package format
+
+import (
+	"io"
+	"strings"
+
+	"github.com/gusbicalho/go-lambda/parse_tree"
+	"github.com/gusbicalho/go-lambda/parser"
+	"github.com/gusbicalho/go-lambda/pretty"
+	"github.com/gusbicalho/go-lambda/tokenizer"
+)
+
+// Canonical surface syntax for lambda programs:
+// minimal parentheses, applications flattened, and terms broken
+// over several lines only when they do not fit the page width.
+// Each definition gets its own line, and comments are kept,
+// each on its own line before the term that follows it.
+// Formatting is idempotent.
+
+func Format(source io.Reader, width uint) (string, error) {
+	tree, err := parser.Parse(tokenizer.New(source))
+	if err != nil {
+		return "", err
+	}
+	return FormatTree(*tree, width), nil
+}
+
+func FormatTree(tree parse_tree.ParseTree, width uint) string {
+	docs := []pretty.Doc{toDoc(normalize(tree))}
+	for _, comment := range tree.TrailingComments {
+		docs = append(docs, pretty.HardLine(), commentDoc(comment))
+	}
+	return pretty.Concat(docs...).Pretty(width) + "\n"
+}
+
+type nodeKind uint8
+
+const (
+	kindVar nodeKind = iota
+	kindLambda
+	kindApp
+	kindLet
+)
+
+// A parse tree without parens, with applications flattened
+// and comments hoisted to the outermost node starting at the same token
+type node struct {
+	kind     nodeKind
+	name     string
+	children []node
+	comments []string
+}
+
+func normalize(tree parse_tree.ParseTree) node {
+	switch item := tree.Item.(type) {
+	case parse_tree.Parens:
+		n := normalize(item.Child)
+		n.comments = concat(tree.Comments, n.comments)
+		return n
+	case parse_tree.Var:
+		return node{kind: kindVar, name: item.Name, comments: tree.Comments}
+	case parse_tree.Lambda:
+		return node{
+			kind:     kindLambda,
+			name:     item.ArgName,
+			children: []node{normalize(item.Body)},
+			comments: tree.Comments,
+		}
+	case parse_tree.Let:
+		return node{
+			kind:     kindLet,
+			name:     item.Name,
+			children: []node{normalize(item.Value), normalize(item.Body)},
+			comments: tree.Comments,
+		}
+	case parse_tree.App:
+		callee := normalize(item.Callee)
+		comments := concat(tree.Comments, callee.comments)
+		callee.comments = nil
+		var children []node
+		if callee.kind == kindApp {
+			children = callee.children
+		} else {
+			children = []node{callee}
+		}
+		children = append(children, normalize(item.Args.First))
+		for _, arg := range item.Args.More {
+			children = append(children, normalize(arg))
+		}
+		return node{kind: kindApp, children: children, comments: comments}
+	default:
+		panic("unknown parse tree")
+	}
+}
+
+func concat(a, b []string) []string {
+	if len(a) == 0 {
+		return b
+	}
+	return append(a[:len(a):len(a)], b...)
+}
+
+func calleeNeedsParens(n node) bool {
+	return n.kind == kindLambda || n.kind == kindLet
+}
+
+// Lambdas and lets extend as far right as possible,
+// so they only need parens when something follows them
+func argNeedsParens(n node, last bool) bool {
+	return n.kind == kindApp || (!last && calleeNeedsParens(n))
+}
+
+func toDoc(n node) pretty.Doc {
+	docs := []pretty.Doc{}
+	for _, comment := range n.comments {
+		docs = append(docs, commentDoc(comment), pretty.HardLine())
+	}
+	switch n.kind {
+	case kindVar:
+		docs = append(docs, pretty.FromString(n.name))
+	case kindLambda:
+		binders := []string{"\\" + n.name + "."}
+		body := n.children[0]
+		for body.kind == kindLambda && len(body.comments) == 0 {
+			binders = append(binders, "\\"+body.name+".")
+			body = body.children[0]
+		}
+		docs = append(docs, pretty.Group(pretty.Concat(
+			pretty.FromString(strings.Join(binders, " ")),
+			pretty.Nest(2, pretty.Concat(pretty.Line(), toDoc(body))),
+		)))
+	case kindLet:
+		// Definitions always get a line of their own
+		docs = append(docs,
+			pretty.FromString("let "+n.name+" ="),
+			pretty.Group(pretty.Concat(
+				pretty.Nest(2, pretty.Concat(pretty.Line(), toDoc(n.children[0]))),
+				pretty.Line(),
+				pretty.FromString("in"),
+			)),
+			pretty.HardLine(),
+			toDoc(n.children[1]),
+		)
+	default:
+		args := []pretty.Doc{}
+		for i, arg := range n.children[1:] {
+			last := i == len(n.children)-2
+			args = append(args, pretty.Line(), operandDoc(arg, argNeedsParens(arg, last)))
+		}
+		callee := n.children[0]
+		docs = append(docs, pretty.Group(pretty.Concat(
+			operandDoc(callee, calleeNeedsParens(callee)),
+			pretty.Nest(2, pretty.Concat(args...)),
+		)))
+	}
+	return pretty.Concat(docs...)
+}
+
+// Comments go before the parens, so that parsing attaches them to the same node
+func operandDoc(n node, parens bool) pretty.Doc {
+	if !parens {
+		return toDoc(n)
+	}
+	docs := []pretty.Doc{}
+	for _, comment := range n.comments {
+		docs = append(docs, commentDoc(comment), pretty.HardLine())
+	}
+	n.comments = nil
+	docs = append(docs,
+		pretty.FromString("("),
+		pretty.Nest(1, toDoc(n)),
+		pretty.FromString(")"),
+	)
+	return pretty.Concat(docs...)
+}
+
+func commentDoc(comment string) pretty.Doc {
+	return pretty.FromString("#" + comment)
+}
@@ -0,0 +1,74 @@
+package format
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/test_helpers"
+)
+
+func deBruijn(t *testing.T, source string) string {
+	return expr.ToLambdaNotation(test_helpers.ParseExpr(t, source), expr.DisplayIndex)
+}
+
+func TestFormat(t *testing.T) {
+	cases := []struct {
+		testName  string
+		width     uint
+		source    string
+		formatted string
+	}{
+		{
+			"Minimal Parens",
+			80,
+			"((\\x. (x x)) ((f) (\\y. y)))",
+			"(\\x. x x) (f \\y. y)\n",
+		},
+		{
+			"Flattened Application",
+			80,
+			"((f a) b) (c d)",
+			"f a b (c d)\n",
+		},
+		{
+			"Lambda Before Another Arg",
+			80,
+			"f (\\x. x) (let a = b in a) c",
+			"f\n  (\\x. x)\n  (let a = b in\n   a)\n  c\n",
+		},
+		{
+			"Definitions And Comments",
+			80,
+			"# booleans\nlet true = \\t. \\f. t in # K\nlet false = (\\t. (\\f. f)) in\ntrue false # the end\n",
+			"# booleans\nlet true = \\t. \\f. t in\n# K\nlet false = \\t. \\f. f in\ntrue false\n# the end\n",
+		},
+		{
+			"Breaks Long Terms",
+			20,
+			"\\f. f (\\x. x x) (g h) somethingLong",
+			"\\f.\n  f\n    (\\x. x x)\n    (g h)\n    somethingLong\n",
+		},
+		{
+			"Comment Inside A Term",
+			80,
+			"(f # inside\n  x) y",
+			"f\n  # inside\n  x\n  y\n",
+		},
+	}
+	for _, c := range cases {
+		formatted, err := Format(strings.NewReader(c.source), c.width)
+		if err != nil {
+			t.Fatalf("%s - %s", c.testName, err)
+		}
+		if formatted != c.formatted {
+			t.Errorf("%s - Expected:\n%s\nActual:\n%s", c.testName, c.formatted, formatted)
+		}
+		if again, _ := Format(strings.NewReader(formatted), c.width); again != formatted {
+			t.Errorf("%s - Not idempotent:\n%s\nThen:\n%s", c.testName, formatted, again)
+		}
+		if deBruijn(t, formatted) != deBruijn(t, c.source) {
+			t.Errorf("%s - Changed meaning:\n%s", c.testName, formatted)
+		}
+	}
+}
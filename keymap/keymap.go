@@ -0,0 +1,221 @@
+package keymap
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Something the user can do from the keyboard
+type Action uint
+
+const (
+	None Action = iota
+	// Tree navigation
+	Parent
+	FirstChild
+	NextSubterm
+	PreviousSubterm
+	NextRedex
+	PreviousRedex
+	// Reduction
+	Reduce
+	Step
+	ReduceAll
+	Undo
+	SwitchStrategy
+	Run
+	Faster
+	Slower
+	// Editing
+	Edit
+	Extract
+	Abstract
+	Inline
+	Rename
+	// Display
+	Expand
+	MoreDepth
+	LessDepth
+	ToggleDiff
+	// Application
+	NextPane
+	PreviousPane
+	Save
+	Help
+	Quit
+)
+
+var actionNames = []string{
+	"none",
+	"parent", "first-child", "next-subterm", "previous-subterm", "next-redex", "previous-redex",
+	"reduce", "step", "reduce-all", "undo", "switch-strategy", "run", "faster", "slower",
+	"edit", "extract", "abstract", "inline", "rename",
+	"expand", "more-depth", "less-depth", "toggle-diff",
+	"next-pane", "previous-pane", "save", "help", "quit",
+}
+
+var actionDescriptions = []string{
+	"do nothing",
+	"parent", "first child", "next subterm", "previous subterm",
+	"next redex, in strategy order", "previous redex, in strategy order",
+	"reduce the focused redex", "reduce the next redex of the strategy", "reduce to normal form",
+	"undo the last reduction", "switch strategy",
+	"run / pause the strategy, any key pauses", "run faster", "run slower",
+	"edit the focused subterm",
+	"extract the focused subterm into a redex", "abstract all copies of the focused subterm",
+	"inline a let, or the let-bound variable", "rename the focused lambda's variable",
+	"expand the focused subterm", "show more of the term", "show less of the term", "show / hide the last step",
+	"next pane", "previous pane", "save the session", "this help", "quit, printing the history",
+}
+
+func (action Action) String() string {
+	return actionNames[action]
+}
+
+func (action Action) Description() string {
+	return actionDescriptions[action]
+}
+
+// Actions are global when they work from any pane, not only from the tree
+func (action Action) Global() bool {
+	switch action {
+	case Step, ReduceAll, Undo, SwitchStrategy, Run, Faster, Slower, NextPane, PreviousPane, Save, Help, Quit:
+		return true
+	}
+	return false
+}
+
+func ParseAction(name string) (Action, error) {
+	for i, actionName := range actionNames {
+		if name == actionName {
+			return Action(i), nil
+		}
+	}
+	return None, errors.New(fmt.Sprint("Unknown action ", name, ", expected one of ", actionNames))
+}
+
+// A key press, as written in keymap files: a single character, Space,
+// a tcell key name like Enter, Left or Ctrl-N, or Alt- followed by one of those
+type Key struct {
+	Key  tcell.Key
+	Rune rune
+	Alt  bool
+}
+
+func KeyOf(event *tcell.EventKey) Key {
+	key := Key{Key: event.Key(), Alt: event.Modifiers()&tcell.ModAlt != 0}
+	if key.Key == tcell.KeyRune {
+		key.Rune = event.Rune()
+	}
+	return key
+}
+
+func (key Key) String() string {
+	var name string
+	switch {
+	case key.Key == tcell.KeyRune && key.Rune == ' ':
+		name = "Space"
+	case key.Key == tcell.KeyRune:
+		name = string(key.Rune)
+	default:
+		name = tcell.KeyNames[key.Key]
+	}
+	if key.Alt {
+		return "Alt-" + name
+	}
+	return name
+}
+
+func ParseKey(name string) (Key, error) {
+	if rest, found := strings.CutPrefix(name, "Alt-"); found && rest != "" {
+		key, err := ParseKey(rest)
+		key.Alt = true
+		return key, err
+	}
+	if name == "Space" {
+		return Key{Key: tcell.KeyRune, Rune: ' '}, nil
+	}
+	if r, size := utf8.DecodeRuneInString(name); size == len(name) && r != utf8.RuneError {
+		return Key{Key: tcell.KeyRune, Rune: r}, nil
+	}
+	for key, keyName := range tcell.KeyNames {
+		if strings.EqualFold(name, keyName) {
+			return Key{Key: key}, nil
+		}
+	}
+	return Key{}, errors.New(fmt.Sprint("Unknown key ", name))
+}
+
+type Keymap map[Key]Action
+
+func (keymap Keymap) Lookup(event *tcell.EventKey) Action {
+	return keymap[KeyOf(event)]
+}
+
+// Keys bound to the action, sorted by name
+func (keymap Keymap) Keys(action Action) []Key {
+	keys := []Key{}
+	for key, bound := range keymap {
+		if bound == action {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b Key) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return keys
+}
+
+func (keymap Keymap) Clone() Keymap {
+	clone := Keymap{}
+	for key, action := range keymap {
+		clone[key] = action
+	}
+	return clone
+}
+
+// Reads a keymap file over a copy of base. Each line binds a key to an action,
+// e.g. "j next-subterm"; "preset vi" starts over from a preset, binding a key
+// to none removes it, and lines starting with # are comments.
+func Load(r io.Reader, base Keymap) (Keymap, error) {
+	keymap := base.Clone()
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, errors.New(fmt.Sprint("Line ", lineNumber, ": expected a key and an action"))
+		}
+		if fields[0] == "preset" {
+			preset, err := Preset(fields[1])
+			if err != nil {
+				return nil, errors.New(fmt.Sprint("Line ", lineNumber, ": ", err))
+			}
+			keymap = preset
+			continue
+		}
+		key, err := ParseKey(fields[0])
+		if err != nil {
+			return nil, errors.New(fmt.Sprint("Line ", lineNumber, ": ", err))
+		}
+		action, err := ParseAction(fields[1])
+		if err != nil {
+			return nil, errors.New(fmt.Sprint("Line ", lineNumber, ": ", err))
+		}
+		if action == None {
+			delete(keymap, key)
+		} else {
+			keymap[key] = action
+		}
+	}
+	return keymap, scanner.Err()
+}
@@ -0,0 +1,58 @@
+package keymap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestPresets(t *testing.T) {
+	for _, name := range PresetNames() {
+		keymap, err := Preset(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, action := range []Action{Parent, FirstChild, NextSubterm, PreviousSubterm, Reduce, Undo, Quit} {
+			if len(keymap.Keys(action)) == 0 {
+				t.Errorf("%s - No key for %s", name, action)
+			}
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	source := `
+# Start from vi, but undo with Backspace and never quit by accident
+preset vi
+Backspace2 undo
+Esc        none
+Alt-x      reduce-all
+`
+	keymap, err := Load(strings.NewReader(source), Keymap{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		event    *tcell.EventKey
+		expected Action
+	}{
+		{tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModNone), NextSubterm},
+		{tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), NextSubterm},
+		{tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone), Undo},
+		{tcell.NewEventKey(tcell.KeyEsc, 0, tcell.ModNone), None},
+		{tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModAlt), ReduceAll},
+		{tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone), Reduce},
+	}
+	for _, c := range cases {
+		if actual := keymap.Lookup(c.event); actual != c.expected {
+			t.Errorf("%s - Expected: %s, Actual: %s", c.event.Name(), c.expected, actual)
+		}
+	}
+
+	for _, bad := range []string{"j", "j jump", "Hyper-j parent", "preset nano"} {
+		if _, err := Load(strings.NewReader(bad), Keymap{}); err == nil {
+			t.Errorf("%s - Expected an error", bad)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package keymap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var presetNames = []string{"default", "vi", "emacs"}
+
+var presets = map[string]string{
+	"default": `
+Left    parent
+Right   first-child
+Up      previous-subterm
+Down    next-subterm
+r       next-redex
+R       previous-redex
+Enter   reduce
+n       step
+N       reduce-all
+u       undo
+s       switch-strategy
+Space   run
+]       faster
+[       slower
+i       edit
+X       extract
+A       abstract
+I       inline
+c       rename
+e       expand
++       more-depth
+-       less-depth
+d       toggle-diff
+Tab     next-pane
+Backtab previous-pane
+Ctrl-S  save
+?       help
+Esc     quit
+`,
+	// hjkl move around the tree, like in vi
+	"vi": `
+preset  default
+h       parent
+l       first-child
+k       previous-subterm
+j       next-subterm
+x       reduce
+G       reduce-all
+`,
+	"emacs": `
+preset  default
+Ctrl-B  parent
+Ctrl-F  first-child
+Ctrl-P  previous-subterm
+Ctrl-N  next-subterm
+Alt-n   next-redex
+Alt-p   previous-redex
+Ctrl-_  undo
+Alt-r   reduce-all
+Ctrl-G  quit
+`,
+}
+
+func PresetNames() []string {
+	return presetNames
+}
+
+func Preset(name string) (Keymap, error) {
+	source, found := presets[name]
+	if !found {
+		return nil, errors.New(fmt.Sprint("Unknown preset ", name, ", expected one of ", presetNames))
+	}
+	return Load(strings.NewReader(source), Keymap{})
+}
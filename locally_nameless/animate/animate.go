@@ -0,0 +1,133 @@
+// Standalone HTML pages that step through a reduction, for slides
+package animate
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	ln_pretty "github.com/gusbicalho/go-lambda/locally_nameless/pretty"
+	"github.com/gusbicalho/go-lambda/pretty"
+)
+
+type frame struct {
+	Title    string
+	Notation template.HTML
+	Before   template.HTML
+	After    template.HTML
+}
+
+// Steps are as recorded by beta_reduce.Trace from start
+func WriteHTML(w io.Writer, title string, start expr.Expr, steps []beta_reduce.BetaRedex) error {
+	frames := []frame{}
+	e := start
+	for i, redex := range steps {
+		before, after := redex.StepDocs()
+		frames = append(frames, frame{
+			Title:    fmt.Sprint("Step ", i+1, " of ", len(steps)),
+			Notation: toHTML(ln_pretty.ToNotationDoc(e, expr.DisplayName), 80),
+			Before:   toHTML(before, 0),
+			After:    toHTML(after, 0),
+		})
+		e = redex.Reduce()
+	}
+
+	last := "Normal form"
+	for range beta_reduce.BetaRedexes(e) {
+		last = fmt.Sprint("Stopped after ", len(steps), " steps")
+		break
+	}
+	frames = append(frames, frame{
+		Title:    last,
+		Notation: toHTML(ln_pretty.ToNotationDoc(e, expr.DisplayName), 80),
+		Before:   toHTML(ln_pretty.ToPrettyDoc(e), 0),
+	})
+
+	return page.Execute(w, struct {
+		Title  string
+		Frames []frame
+	}{title, frames})
+}
+
+// The HTML renderer escapes all text, so its output is safe to embed
+func toHTML(doc pretty.Doc, width uint) template.HTML {
+	return template.HTML(doc.PrettyWith(pretty.HTML, width))
+}
+
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: sans-serif; margin: 2em; }
+  pre { font-family: monospace; font-size: 1.1em; line-height: 1.2; }
+  .steps { display: flex; gap: 3em; align-items: flex-start; }
+  .frame[hidden] { display: none; }
+  .notation { color: #555; }
+  .lambda-binder { font-weight: bold; color: #0a7a8a; }
+  .lambda-bound-var { color: #0a7a8a; }
+  .lambda-free-var { color: #a06800; }
+  .lambda-redex { background: #fde8c8; }
+  .lambda-reduct { background: #d8ecff; }
+  .lambda-argument { background: #c8f0c8; font-weight: bold; }
+  .lambda-highlight { background: #eee; }
+  .lambda-elided { font-style: italic; color: #a0a; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<nav>
+  <button id="prev">◀</button>
+  <button id="play">▶ play</button>
+  <button id="next">▶</button>
+  <span id="counter"></span>
+</nav>
+{{range .Frames}}
+<section class="frame" hidden>
+  <h2>{{.Title}}</h2>
+  <pre class="notation">{{.Notation}}</pre>
+  <div class="steps">
+    <pre>{{.Before}}</pre>
+    {{if .After}}<pre>{{.After}}</pre>{{end}}
+  </div>
+</section>
+{{end}}
+<script>
+  const frames = document.querySelectorAll(".frame");
+  const counter = document.getElementById("counter");
+  let current = 0;
+  let timer = null;
+  function show(i) {
+    current = Math.max(0, Math.min(frames.length - 1, i));
+    frames.forEach((frame, j) => { frame.hidden = j !== current; });
+    counter.textContent = (current + 1) + " / " + frames.length;
+  }
+  function play() {
+    if (timer !== null) {
+      clearInterval(timer);
+      timer = null;
+      return;
+    }
+    timer = setInterval(() => {
+      if (current === frames.length - 1) {
+        play();
+      } else {
+        show(current + 1);
+      }
+    }, 1500);
+  }
+  document.getElementById("prev").onclick = () => show(current - 1);
+  document.getElementById("next").onclick = () => show(current + 1);
+  document.getElementById("play").onclick = play;
+  document.addEventListener("keydown", event => {
+    if (event.key === "ArrowLeft") show(current - 1);
+    if (event.key === "ArrowRight" || event.key === " ") show(current + 1);
+  });
+  show(0);
+</script>
+</body>
+</html>
+`))
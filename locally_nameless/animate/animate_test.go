@@ -0,0 +1,66 @@
+package animate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+)
+
+func TestWriteHTML(t *testing.T) {
+	// (\x. x) ((\y. y) a<b&c)
+	id := func(name string) expr.Expr { return expr.NewLambda(name, expr.NewBound(0)) }
+	start := expr.NewApp(id("x"), expr.NewApp(id("y"), expr.NewFree("a<b&c")))
+	steps := beta_reduce.Trace(start, beta_reduce.NormalOrder, 10)
+	out := strings.Builder{}
+	if err := WriteHTML(&out, `(\x. x) <&>`, start, steps); err != nil {
+		t.Fatal(err)
+	}
+	page := out.String()
+	if frames := strings.Count(page, `<section class="frame"`); frames != 3 {
+		t.Fatalf("Expected 3 frames, got %d", frames)
+	}
+	for _, expected := range []string{
+		`<title>(\x. x) &lt;&amp;&gt;</title>`,
+		"Step 1 of 2", "Step 2 of 2", "Normal form",
+		// The notation of each frame, with backslashes kept as they are
+		`<pre class="notation">(\<span class="lambda-binder">x</span>. <span class="lambda-bound-var">x</span>) ((\<span`,
+		`<pre class="notation">(\<span class="lambda-binder">y</span>. <span class="lambda-bound-var">y</span>) <span`,
+		`<pre class="notation"><span class="lambda-free-var">a&lt;b&amp;c</span></pre>`,
+	} {
+		if !strings.Contains(page, expected) {
+			t.Errorf("Expected the page to contain %s", expected)
+		}
+	}
+	for _, unexpected := range []string{"a<b", "&amp;lt;", `\\`} {
+		if strings.Contains(page, unexpected) {
+			t.Errorf("Expected the page not to contain %s", unexpected)
+		}
+	}
+
+	// Each step shows the redex, then its reduct, with the argument and its copies marked
+	highlights := [][]string{
+		{
+			`<pre><span class="lambda-redex">λ<span class="lambda-binder">x</span> ─┬─</span>`,
+			`<span class="lambda-redex">└► <span class="lambda-argument">λ<span class="lambda-binder">y</span> ─┬─</span></span>`,
+			`<pre><span class="lambda-reduct"><span class="lambda-argument">λ<span class="lambda-binder">y</span> ─┬─</span></span>`,
+		},
+		{
+			`<pre><span class="lambda-redex">λ<span class="lambda-binder">y</span> ─┬─</span>`,
+			`<span class="lambda-redex">└► <span class="lambda-argument"><span class="lambda-free-var">a&lt;b&amp;c</span></span></span></pre>`,
+			`<pre><span class="lambda-reduct"><span class="lambda-argument"><span class="lambda-free-var">a&lt;b&amp;c</span></span></span></pre>`,
+		},
+		{},
+	}
+	for i, frame := range strings.Split(page, `<section class="frame"`)[1:] {
+		for _, expected := range highlights[i] {
+			if !strings.Contains(frame, expected) {
+				t.Errorf("Expected frame %d to contain %s", i+1, expected)
+			}
+		}
+		if len(highlights[i]) == 0 && strings.Contains(frame, "lambda-redex") {
+			t.Errorf("Expected no redex in the normal form frame")
+		}
+	}
+}
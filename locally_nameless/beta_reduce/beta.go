@@ -1,6 +1,7 @@
 package beta_reduce
 
 import (
+	"fmt"
 	"iter"
 
 	"github.com/gusbicalho/go-lambda/lazy"
@@ -24,13 +25,64 @@ type BetaRedex struct {
 func (redex BetaRedex) ToPrettyDoc(_ any) pretty.Doc {
 	return redex.Hole.ToPrettyDoc(
 		func(ctx expr.DisplayContext) pretty.Doc {
-			return pretty.TViewInvert(
+			return pretty.Annotate(pretty.Redex,
 				ln_pretty.ExprToPrettyDoc(expr.NewApp(redex.Lambda, redex.Arg), ctx),
 			)
 		},
 	)
 }
 
+// The term before the redex is contracted, with the redex and its argument marked,
+// and the term after, with the reduct and the copies of the argument marked
+func (redex BetaRedex) StepDocs() (before pretty.Doc, after pretty.Doc) {
+	before = redex.Hole.ToPrettyDoc(
+		func(ctx expr.DisplayContext) pretty.Doc {
+			options := ln_pretty.Options{Marks: []ln_pretty.Mark{
+				{Path: expr.Path{1}, Annotation: pretty.Argument},
+			}}
+			return pretty.Annotate(pretty.Redex,
+				ln_pretty.ExprAtToPrettyDoc(expr.NewApp(redex.Lambda, redex.Arg), ln_pretty.RootPosition(ctx, options)),
+			)
+		},
+	)
+	after = redex.Hole.ToPrettyDoc(
+		func(ctx expr.DisplayContext) pretty.Doc {
+			options := ln_pretty.Options{}
+			for _, path := range redex.ArgumentCopies() {
+				options.Marks = append(options.Marks, ln_pretty.Mark{Path: path, Annotation: pretty.Argument})
+			}
+			return pretty.Annotate(pretty.Reduct,
+				ln_pretty.ExprAtToPrettyDoc(BetaReduce(redex.Lambda, redex.Arg), ln_pretty.RootPosition(ctx, options)),
+			)
+		},
+	)
+	return before, after
+}
+
+// Where the argument is copied to in the reduct, relative to the reduct.
+// Substitution keeps the shape of the body, so these are the paths
+// of the occurrences of the lambda's variable in its body.
+func (redex BetaRedex) ArgumentCopies() []expr.Path {
+	return occurrences(redex.Lambda.Body(), 0, expr.Path{})
+}
+
+func occurrences(e expr.Expr, index uint, path expr.Path) []expr.Path {
+	switch e := e.(type) {
+	case expr.BoundVar:
+		if e.Index() == index {
+			return []expr.Path{path}
+		}
+	case expr.Lambda:
+		return occurrences(e.Body(), index+1, path.Child(0))
+	case expr.App:
+		return append(
+			occurrences(e.Callee(), index, path.Child(0)),
+			occurrences(e.Arg(), index, path.Child(1))...,
+		)
+	}
+	return nil
+}
+
 func AsBetaRedex(e expr.Expr) *BetaRedex {
 	if app, ok := e.(expr.App); ok {
 		if callee, ok := app.Callee().(expr.Lambda); ok {
@@ -93,37 +145,44 @@ func (v substVisit) CaseApp(body expr.App) expr.Expr {
 	)
 }
 func (v substVisit) CaseLambda(body expr.Lambda) expr.Expr {
-	shiftedArg := lazy.New(func() expr.Expr { return shift(v.arg.Get(), 0) })
+	shiftedArg := lazy.New(func() expr.Expr { return Shift(v.arg.Get(), 1, 0) })
 	return expr.NewLambda(body.ArgName(), subst(body.Body(), shiftedArg, v.index+1))
 }
 
-func shift(e expr.Expr, underBinders uint) expr.Expr {
-	return expr.CaseExpr(e, shiftVisit{underBinders})
+// Adds by to the indexes of variables bound outside e, i.e. at least cutoff.
+// A negative by removes binders from around e, so no variable may refer to them:
+// Shift panics rather than wrap around or rebind such a variable.
+func Shift(e expr.Expr, by int, cutoff uint) expr.Expr {
+	return expr.CaseExpr(e, shiftVisit{by, cutoff})
 }
 
 type shiftVisit struct {
-	underBinders uint
+	by     int
+	cutoff uint
 }
 
 func (v shiftVisit) CaseBound(e expr.BoundVar) expr.Expr {
-	if e.Index() < v.underBinders {
+	if e.Index() < v.cutoff {
 		// References to variables bound within the term being shifted
 		// They stay the same
 		return e
 	}
 	// References to variables outside the term being shifted
-	// They get increased
-	return expr.NewBound(e.Index() + 1)
+	// They get moved by the given amount
+	if int(e.Index()-v.cutoff)+v.by < 0 {
+		panic(fmt.Sprint("shifting by ", v.by, " removes the binder of variable ", e.Index(), " under ", v.cutoff, " binders"))
+	}
+	return expr.NewBound(uint(int(e.Index()) + v.by))
 }
 func (v shiftVisit) CaseFree(e expr.FreeVar) expr.Expr {
 	return e
 }
 func (v shiftVisit) CaseApp(e expr.App) expr.Expr {
 	return expr.NewApp(
-		shift(e.Callee(), v.underBinders),
-		shift(e.Arg(), v.underBinders),
+		Shift(e.Callee(), v.by, v.cutoff),
+		Shift(e.Arg(), v.by, v.cutoff),
 	)
 }
 func (v shiftVisit) CaseLambda(e expr.Lambda) expr.Expr {
-	return expr.NewLambda(e.ArgName(), shift(e.Body(), v.underBinders+1))
+	return expr.NewLambda(e.ArgName(), Shift(e.Body(), v.by, v.cutoff+1))
 }
@@ -2,9 +2,15 @@ package beta_reduce
 
 import (
 	"fmt"
+	"math/rand/v2"
+	"slices"
 	"testing"
 
 	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/generate"
+	"github.com/gusbicalho/go-lambda/locally_nameless/metrics"
+	"github.com/gusbicalho/go-lambda/locally_nameless/validate"
+	"github.com/gusbicalho/go-lambda/locally_nameless/walk"
 )
 
 func assertExprRendersAs(t *testing.T, testName string, e expr.Expr, expected string) {
@@ -14,6 +20,23 @@ func assertExprRendersAs(t *testing.T, testName string, e expr.Expr, expected st
 	}
 }
 
+// Reducing a well-scoped term must yield a well-scoped term,
+// and must not introduce free variables
+func assertReducePreservesScope(t *testing.T, testName string, before expr.Expr, after expr.Expr) {
+	if len(validate.Validate(before)) > 0 {
+		return
+	}
+	for _, err := range validate.Validate(after) {
+		t.Errorf("%s - %s\nIn: %s", testName, err, expr.ToLambdaNotation(after, expr.DisplayBoth))
+	}
+	freeBefore := metrics.Of(before).FreeVars
+	for _, name := range metrics.Of(after).FreeVars {
+		if !slices.Contains(freeBefore, name) {
+			t.Errorf("%s - Unexpected free variable %s", testName, name)
+		}
+	}
+}
+
 func TestBetaReduce(t *testing.T) {
 	cases := []struct {
 		testName  string
@@ -152,5 +175,172 @@ func TestBetaReduce(t *testing.T) {
 		testName := fmt.Sprint("Case ", i+1, " :", c.testName)
 		assertExprRendersAs(t, testName, expr.NewApp(c.lambda, c.arg), c.rendersAs)
 		assertExprRendersAs(t, testName, BetaReduce(c.lambda, c.arg), c.reducesTo)
+		assertReducePreservesScope(t, testName, expr.NewApp(c.lambda, c.arg), BetaReduce(c.lambda, c.arg))
+
+		// The same redex, filled into a context with binders of its own,
+		// next to a redex that refers to those binders
+		outerRedex := expr.NewApp(expr.NewLambda("y", expr.NewBound(2)), expr.NewBound(0))
+		inContext := expr.NewLambda("outer", expr.NewApp(
+			expr.NewFree("ctx"),
+			expr.NewLambda("inner", expr.NewApp(expr.NewApp(c.lambda, c.arg), outerRedex)),
+		))
+		for redex := range BetaRedexes(inContext) {
+			assertReducePreservesScope(t, testName+" (in context)", inContext, redex.Reduce())
+		}
+	}
+}
+
+func TestStrategies(t *testing.T) {
+	omega := expr.NewLambda("x", expr.NewApp(expr.NewBound(0), expr.NewBound(0)))
+	e := expr.NewApp(expr.NewLambda("x", expr.NewFree("y")), expr.NewApp(omega, omega))
+
+	normal := Trace(e, NormalOrder, 10)
+	if len(normal) != 1 {
+		t.Fatalf("Normal order - Expected 1 step, got %d", len(normal))
+	}
+	assertExprRendersAs(t, "Normal order", normal[0].Reduce(), "y")
+
+	applicative := Trace(e, ApplicativeOrder, 10)
+	if len(applicative) != 10 {
+		t.Fatalf("Applicative order - Expected to stop at 10 steps, got %d", len(applicative))
+	}
+	assertExprRendersAs(t, "Applicative order", applicative[9].Reduce(), "(\\x. y) ((\\x. x x) (\\x. x x))")
+}
+
+func TestAround(t *testing.T) {
+	omega := expr.NewLambda("x", expr.NewApp(expr.NewBound(0), expr.NewBound(0)))
+	// Redexes at / and /1
+	e := expr.NewApp(expr.NewLambda("x", expr.NewFree("y")), expr.NewApp(omega, omega))
+
+	pathOf := func(redex *BetaRedex) string {
+		if redex == nil {
+			return "none"
+		}
+		return redex.Hole.Path().String()
 	}
+	cases := []struct {
+		strategy Strategy
+		path     expr.Path
+		before   string
+		after    string
+	}{
+		{NormalOrder, expr.Path{}, "none", "/1"},
+		{NormalOrder, expr.Path{0}, "/", "/1"},
+		{NormalOrder, expr.Path{1, 0}, "/1", "none"},
+		{ApplicativeOrder, expr.Path{0}, "none", "/1"},
+		{ApplicativeOrder, expr.Path{1}, "none", "/"},
+		{ApplicativeOrder, expr.Path{1, 0}, "none", "/1"},
+		{ApplicativeOrder, expr.Path{}, "/1", "none"},
+	}
+	for _, c := range cases {
+		before, after := c.strategy.Around(e, c.path)
+		if pathOf(before) != c.before || pathOf(after) != c.after {
+			t.Errorf("%s from %s - Expected %s and %s, got %s and %s", c.strategy, c.path, c.before, c.after, pathOf(before), pathOf(after))
+		}
+	}
+}
+
+func TestArgumentCopies(t *testing.T) {
+	// \f. \x. f (f x)
+	twice := expr.NewLambda("f", expr.NewLambda("x",
+		expr.NewApp(expr.NewBound(1), expr.NewApp(expr.NewBound(1), expr.NewBound(0))),
+	))
+	// \x. \y. #argument (\z. x) (y x), with a free variable no parsed term can have
+	odd := expr.NewLambda("x", expr.NewLambda("y", expr.NewApp(
+		expr.NewApp(expr.NewFree("#argument"), expr.NewLambda("z", expr.NewBound(2))),
+		expr.NewApp(expr.NewBound(0), expr.NewBound(1)),
+	)))
+	cases := []struct {
+		lambda   expr.Expr
+		expected []expr.Path
+	}{
+		{twice, []expr.Path{{0, 0}, {0, 1, 0}}},
+		{odd, []expr.Path{{0, 0, 1, 0}, {0, 1, 1}}},
+	}
+	for _, c := range cases {
+		redex := AsBetaRedex(expr.NewApp(c.lambda, expr.NewFree("g")))
+		actual := redex.ArgumentCopies()
+		if !slices.EqualFunc(actual, c.expected, slices.Equal) {
+			t.Errorf("%s - Expected %v, got %v", expr.ToLambdaNotation(c.lambda, expr.DisplayName), c.expected, actual)
+		}
+		// The copies are where the argument is in the reduct
+		reduct := redex.Reduce()
+		for _, path := range actual {
+			if copy, ok := expr.At(reduct, path); !ok || !expr.AlphaEqual(copy, redex.Arg) {
+				t.Errorf("%s - Expected the argument at %s", expr.ToLambdaNotation(c.lambda, expr.DisplayName), path)
+			}
+		}
+	}
+}
+
+func TestReducePreservesScopeProperty(t *testing.T) {
+	allReductsScoped := func(e expr.Expr) bool {
+		for redex := range BetaRedexes(e) {
+			if len(validate.Validate(redex.Reduce())) > 0 {
+				return false
+			}
+		}
+		return true
+	}
+	if e, ok := generate.Check(rand.New(rand.NewPCG(1, 2)), 500, 30, allReductsScoped); !ok {
+		t.Errorf("A reduct is not well-scoped: %s", expr.ToLambdaNotation(e, expr.DisplayBoth))
+	}
+}
+
+// The normal form, or nil when none was reached within the limits
+func normalize(e expr.Expr, strategy Strategy) expr.Expr {
+	for range 100 {
+		redex := strategy.Next(e)
+		if redex == nil {
+			return e
+		}
+		if e = redex.Reduce(); metrics.Of(e).Size > 2000 {
+			return nil
+		}
+	}
+	return nil
+}
+
+func TestStrategiesAgreeProperty(t *testing.T) {
+	agree := func(e expr.Expr) bool {
+		normal, applicative := normalize(e, NormalOrder), normalize(e, ApplicativeOrder)
+		return normal == nil || applicative == nil ||
+			expr.ToLambdaNotation(normal, expr.DisplayDeBruijn) == expr.ToLambdaNotation(applicative, expr.DisplayDeBruijn)
+	}
+	if e, ok := generate.Check(rand.New(rand.NewPCG(1, 2)), 500, 30, agree); !ok {
+		t.Errorf("The strategies reach different normal forms from %s", expr.ToLambdaNotation(e, expr.DisplayBoth))
+	}
+}
+
+func TestShiftUnshiftProperty(t *testing.T) {
+	// Subterms, so that some variables are bound outside them
+	unshiftReverses := func(e expr.Expr) bool {
+		for _, subterm := range walk.Post(e) {
+			original := expr.ToLambdaNotation(subterm, expr.DisplayBoth)
+			for by := 1; by <= 3; by++ {
+				for cutoff := uint(0); cutoff <= 2; cutoff++ {
+					if expr.ToLambdaNotation(Shift(Shift(subterm, by, cutoff), -by, cutoff), expr.DisplayBoth) != original {
+						return false
+					}
+				}
+			}
+		}
+		return true
+	}
+	if e, ok := generate.Check(rand.New(rand.NewPCG(1, 2)), 500, 30, unshiftReverses); !ok {
+		t.Errorf("Unshifting does not reverse a shift in %s", expr.ToLambdaNotation(e, expr.DisplayBoth))
+	}
+}
+
+func TestShiftRemovingUsedBinderPanics(t *testing.T) {
+	// \x. x 1, where 1 refers to the binder just outside
+	e := expr.NewLambda("x", expr.NewApp(expr.NewBound(0), expr.NewBound(1)))
+	assertExprRendersAs(t, "Unused binder", Shift(expr.NewLambda("x", expr.NewBound(0)), -1, 0), "\\x. x")
+	assertExprRendersAs(t, "Binders further out", Shift(expr.NewLambda("x", expr.NewBound(2)), -1, 0), "\\x. 1:<outofscope>")
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic")
+		}
+	}()
+	Shift(e, -1, 0)
 }
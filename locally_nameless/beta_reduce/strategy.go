@@ -0,0 +1,120 @@
+package beta_reduce
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/hole"
+	"github.com/gusbicalho/go-lambda/locally_nameless/walk"
+)
+
+// Which redex to contract next
+type Strategy uint
+
+const (
+	// Leftmost-outermost redex first; reaches the normal form whenever there is one
+	NormalOrder Strategy = iota
+	// Leftmost-innermost redex first, so arguments are reduced before they are substituted
+	ApplicativeOrder
+)
+
+// Every strategy, in the order the TUI switches through them
+var Strategies = []Strategy{NormalOrder, ApplicativeOrder}
+
+var strategyNames = []string{"normal", "applicative"}
+
+func (strategy Strategy) String() string {
+	return strategyNames[strategy]
+}
+
+func ParseStrategy(name string) (Strategy, error) {
+	for i, strategyName := range strategyNames {
+		if name == strategyName {
+			return Strategy(i), nil
+		}
+	}
+	return 0, errors.New(fmt.Sprint("Unknown strategy ", name, ", expected one of ", strategyNames))
+}
+
+// Subterms of e in the order the strategy looks for redexes:
+// pre-order for NormalOrder, post-order for ApplicativeOrder
+func (strategy Strategy) Order(e expr.Expr) iter.Seq2[hole.Hole, expr.Expr] {
+	if strategy == ApplicativeOrder {
+		return walk.Post(e)
+	}
+	return walk.ToSeq(walk.Pre(e))
+}
+
+func (strategy Strategy) Redexes(e expr.Expr) iter.Seq[BetaRedex] {
+	return func(yield func(BetaRedex) bool) {
+		for h, e := range strategy.Order(e) {
+			if redex := AsBetaRedex(e); redex != nil {
+				redex.Hole = hole.ComposeHoles(h, redex.Hole)
+				if !yield(*redex) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Nil when e is in normal form
+func (strategy Strategy) Next(e expr.Expr) *BetaRedex {
+	for redex := range strategy.Redexes(e) {
+		return &redex
+	}
+	return nil
+}
+
+// The redexes nearest to the subterm at path, before and after it in the strategy's order.
+// Either is nil when there is no redex on that side.
+func (strategy Strategy) Around(e expr.Expr, path expr.Path) (before *BetaRedex, after *BetaRedex) {
+	passed := false
+	for redex := range strategy.Redexes(e) {
+		redexPath := redex.Hole.Path()
+		if slices.Equal(redexPath, path) {
+			passed = true
+			continue
+		}
+		if !passed {
+			passed = strategy.visitsAfter(redexPath, path)
+		}
+		if passed {
+			return before, &redex
+		}
+		before = &redex
+	}
+	return before, nil
+}
+
+// Whether the strategy visits the subterm at a after the one at b
+func (strategy Strategy) visitsAfter(a expr.Path, b expr.Path) bool {
+	if strategy == ApplicativeOrder {
+		// Children come before their parents
+		if b.HasPrefix(a) {
+			return true
+		}
+		if a.HasPrefix(b) {
+			return false
+		}
+	}
+	return slices.Compare(a, b) > 0
+}
+
+// Contracts redexes chosen by the strategy, until the term is normal or
+// maxSteps were taken. Each redex is in the term left by the one before.
+func Trace(e expr.Expr, strategy Strategy, maxSteps uint) []BetaRedex {
+	steps := []BetaRedex{}
+	for uint(len(steps)) < maxSteps {
+		redex := strategy.Next(e)
+		if redex == nil {
+			break
+		}
+		steps = append(steps, *redex)
+		e = redex.Reduce()
+	}
+	return steps
+}
@@ -0,0 +1,103 @@
+// Structural diffs between terms, e.g. before and after a reduction step
+package diff
+
+import (
+	"slices"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
+	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/hole"
+	ln_pretty "github.com/gusbicalho/go-lambda/locally_nameless/pretty"
+	"github.com/gusbicalho/go-lambda/locally_nameless/walk"
+	"github.com/gusbicalho/go-lambda/pretty"
+)
+
+type Diff struct {
+	// Context shared by both terms
+	Hole hole.Hole
+	// Subterms that differ, in the hole
+	Before, After ln.Expr
+	// Where copies of a substituted argument are, from After
+	Copies []ln.Path
+}
+
+// Nil when the terms are alpha-equivalent
+func Of(before, after ln.Expr) *Diff {
+	nav := walk.ToNav(before)
+	for {
+		children := differingChildren(before, after)
+		if children == nil {
+			return nil
+		}
+		if len(children) != 1 {
+			break
+		}
+		nav = *nav.Child(children[0])
+		before, _ = ln.At(before, ln.Path{children[0]})
+		after, _ = ln.At(after, ln.Path{children[0]})
+	}
+	return &Diff{Hole: nav.Focus().Hole, Before: before, After: after}
+}
+
+// Nil when the terms are alpha-equivalent, all children when their roots differ
+func differingChildren(before, after ln.Expr) []uint {
+	if ln.AlphaEqual(before, after) {
+		return nil
+	}
+	switch before := before.(type) {
+	case ln.Lambda:
+		if _, ok := after.(ln.Lambda); ok {
+			return []uint{0}
+		}
+	case ln.App:
+		if after, ok := after.(ln.App); ok {
+			children := []uint{}
+			if !ln.AlphaEqual(before.Callee(), after.Callee()) {
+				children = append(children, 0)
+			}
+			if !ln.AlphaEqual(before.Arg(), after.Arg()) {
+				children = append(children, 1)
+			}
+			return children
+		}
+	}
+	return []uint{}
+}
+
+// The redex replaced by its reduct
+func OfStep(redex beta_reduce.BetaRedex) Diff {
+	return Diff{
+		Hole:   redex.Hole,
+		Before: ln.NewApp(redex.Lambda, redex.Arg),
+		After:  beta_reduce.BetaReduce(redex.Lambda, redex.Arg),
+		Copies: redex.ArgumentCopies(),
+	}
+}
+
+func (d Diff) ToPrettyDoc() pretty.Doc {
+	return d.ToPrettyDocWith(ln_pretty.Options{})
+}
+
+// The context is elided according to options, while what changed is shown in full
+func (d Diff) ToPrettyDocWith(options ln_pretty.Options) pretty.Doc {
+	options.Expanded = append(slices.Clip(options.Expanded), d.Hole.Path())
+	return d.Hole.ToPrettyDocWith(options,
+		func(pos ln_pretty.Position) pretty.Doc {
+			copies := []ln_pretty.Mark{}
+			for _, path := range d.Copies {
+				copies = append(copies, ln_pretty.Mark{
+					Path:       append(slices.Clone(pos.Path()), path...),
+					Annotation: pretty.Argument,
+				})
+			}
+			return pretty.Sequence(
+				pretty.PrefixLines([]string{"- "}, pretty.Annotate(pretty.Removed,
+					ln_pretty.ExprAtToPrettyDoc(d.Before, pos.WithMarks()),
+				)),
+				pretty.PrefixLines([]string{"+ "}, pretty.Annotate(pretty.Added,
+					ln_pretty.ExprAtToPrettyDoc(d.After, pos.WithMarks(copies...)),
+				)),
+			)
+		},
+	)
+}
@@ -0,0 +1,54 @@
+package diff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
+	"github.com/gusbicalho/go-lambda/locally_nameless/diff"
+	"github.com/gusbicalho/go-lambda/test_helpers"
+)
+
+func TestOf(t *testing.T) {
+	cases := []struct {
+		before, after string
+		// Hole path, or "" when the terms are alpha-equivalent
+		path string
+	}{
+		{"\\x. x y", "\\z. z y", ""},
+		{"\\x. f (x y)", "\\x. f (x z)", "/0/1/1"},
+		{"\\x. f (x y)", "\\x. g (x z)", "/0"},
+		{"a", "\\x. x", "/"},
+	}
+	for _, c := range cases {
+		d := diff.Of(test_helpers.ParseExpr(t, c.before), test_helpers.ParseExpr(t, c.after))
+		path := ""
+		if d != nil {
+			path = d.Hole.Path().String()
+		}
+		if path != c.path {
+			t.Errorf("%s -> %s - Expected %q, got %q", c.before, c.after, c.path, path)
+		}
+	}
+}
+
+func TestOfStep(t *testing.T) {
+	e := test_helpers.ParseExpr(t, "\\z. (\\f. f (f z)) g")
+	redex := beta_reduce.NormalOrder.Next(e)
+	expected := strings.Join([]string{
+		"λz ─┬─",
+		"    │ - λf ─┬─",
+		"    │ -     │ 0:f",
+		"    │ -     │ └► 0:f",
+		"    │ -     │    └► 1:z",
+		"    │ -     ╰─",
+		"    │ - └► g",
+		"    │ + g",
+		"    │ + └► g",
+		"    │ +    └► 0:z",
+		"    ╰─",
+	}, "\n")
+	if actual := diff.OfStep(*redex).ToPrettyDoc().String(); actual != expected {
+		t.Errorf("Expected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
@@ -2,12 +2,14 @@ package expr
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/gusbicalho/go-lambda/stack"
 )
 
 type DisplayContext struct {
 	bound             stack.Stack[string]
+	reserved          stack.Stack[string]
 	displayBoundVarAs DisplayBoundVarAs
 }
 
@@ -17,6 +19,8 @@ const (
 	DisplayBoth DisplayBoundVarAs = iota
 	DisplayName
 	DisplayIndex
+	// Indexes only, with binders left unnamed
+	DisplayDeBruijn
 )
 
 func EmptyContext() DisplayContext {
@@ -28,6 +32,28 @@ func (ctx DisplayContext) WithDisplayBoundVarAs(displayAs DisplayBoundVarAs) Dis
 	return ctx
 }
 
+func (ctx DisplayContext) DisplayBoundVarAs() DisplayBoundVarAs {
+	return ctx.displayBoundVarAs
+}
+
+// Reserved names are never chosen by BindFree,
+// e.g. to keep binders from capturing free variables with the same name
+func (ctx DisplayContext) WithReserved(names ...string) DisplayContext {
+	for _, name := range names {
+		ctx.reserved = ctx.reserved.Push(name)
+	}
+	return ctx
+}
+
+func (ctx DisplayContext) BoundName(index uint) (string, bool) {
+	return ctx.bound.Nth(index, "")
+}
+
+// Names of the bound variables, innermost first
+func (ctx DisplayContext) BoundNames() []string {
+	return slices.Collect(ctx.bound.Items())
+}
+
 func (ctx DisplayContext) BindFree(name string) (DisplayContext, string) {
 	if ctx.isBound(name) {
 		for i := 0; ; i++ {
@@ -51,5 +77,10 @@ func (ctx DisplayContext) isBound(name string) bool {
 			return true
 		}
 	}
+	for reserved := range ctx.reserved.Items() {
+		if name == reserved {
+			return true
+		}
+	}
 	return false
 }
@@ -69,3 +69,22 @@ func (expr App) Callee() Expr     { return expr.callee }
 func (expr App) Arg() Expr        { return expr.arg }
 
 func (App) sealed() {}
+
+// Binder names do not matter
+func AlphaEqual(a Expr, b Expr) bool {
+	switch a := a.(type) {
+	case FreeVar:
+		b, ok := b.(FreeVar)
+		return ok && a.Name() == b.Name()
+	case BoundVar:
+		b, ok := b.(BoundVar)
+		return ok && a.Index() == b.Index()
+	case Lambda:
+		b, ok := b.(Lambda)
+		return ok && AlphaEqual(a.Body(), b.Body())
+	case App:
+		b, ok := b.(App)
+		return ok && AlphaEqual(a.Callee(), b.Callee()) && AlphaEqual(a.Arg(), b.Arg())
+	}
+	return false
+}
@@ -42,7 +42,7 @@ func (expr BoundVar) WriteLambdaNotation(ctx DisplayContext, writer io.StringWri
 
 func (expr BoundVar) writeLambdaNotation(ctx DisplayContext, writer io.StringWriter) error {
 	switch ctx.displayBoundVarAs {
-	case DisplayIndex:
+	case DisplayIndex, DisplayDeBruijn:
 		return writeStrings(writer, fmt.Sprint(expr.index))
 	case DisplayName:
 		if name, found := ctx.bound.Nth(expr.index, ""); found {
@@ -58,6 +58,9 @@ func (expr BoundVar) writeLambdaNotation(ctx DisplayContext, writer io.StringWri
 
 func (expr Lambda) writeLambdaNotation(ctx DisplayContext, writer io.StringWriter) error {
 	ctx, argName := ctx.BindFree(expr.argName)
+	if ctx.displayBoundVarAs == DisplayDeBruijn {
+		argName = ""
+	}
 	if err := writeStrings(writer, "\\", argName, ". "); err != nil {
 		return err
 	}
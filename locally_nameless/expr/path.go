@@ -0,0 +1,80 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Child indexes leading from the root of a term to one of its subterms.
+// A Lambda's body is child 0; an App's callee is child 0 and its arg is child 1.
+type Path []uint
+
+func (path Path) String() string {
+	builder := strings.Builder{}
+	builder.WriteString("/")
+	for i, child := range path {
+		if i > 0 {
+			builder.WriteString("/")
+		}
+		builder.WriteString(fmt.Sprint(child))
+	}
+	return builder.String()
+}
+
+// Reads paths as written by String, e.g. /0/1
+func ParsePath(s string) (Path, error) {
+	rest, found := strings.CutPrefix(s, "/")
+	if !found {
+		return nil, errors.New(fmt.Sprint("Invalid path ", s, ", expected it to start with /"))
+	}
+	path := Path{}
+	if rest == "" {
+		return path, nil
+	}
+	for _, child := range strings.Split(rest, "/") {
+		index, err := strconv.ParseUint(child, 10, 0)
+		if err != nil || index > 1 {
+			return nil, errors.New(fmt.Sprint("Invalid path ", s, ", expected children 0 or 1"))
+		}
+		path = append(path, uint(index))
+	}
+	return path, nil
+}
+
+// Returns a new path, leaving the receiver untouched
+func (path Path) Child(child uint) Path {
+	extended := make(Path, len(path), len(path)+1)
+	copy(extended, path)
+	return append(extended, child)
+}
+
+func (path Path) HasPrefix(prefix Path) bool {
+	return len(prefix) <= len(path) && slices.Equal(path[:len(prefix)], prefix)
+}
+
+func At(e Expr, path Path) (Expr, bool) {
+	for _, child := range path {
+		switch current := e.(type) {
+		case Lambda:
+			if child != 0 {
+				return nil, false
+			}
+			e = current.body
+		case App:
+			switch child {
+			case 0:
+				e = current.callee
+			case 1:
+				e = current.arg
+			default:
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+	}
+	return e, true
+}
@@ -0,0 +1,123 @@
+// Random well-scoped terms for property tests, with shrinking of counterexamples
+package generate
+
+import (
+	"iter"
+	"math/rand/v2"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+)
+
+// Few names, so that binders often shadow each other and clash with free variables
+var (
+	FreeNames   = []string{"a", "b", "x"}
+	BinderNames = []string{"x", "y", "z"}
+)
+
+// A term of exactly size nodes, whose bound variables all refer to its own binders
+func Expr(r *rand.Rand, size uint) expr.Expr {
+	return ExprIn(r, size, 0)
+}
+
+// A term to go under that many binders, which it may refer to
+func ExprIn(r *rand.Rand, size uint, binders uint) expr.Expr {
+	if size <= 1 {
+		if binders > 0 && r.IntN(4) > 0 {
+			return expr.NewBound(uint(r.IntN(int(binders))))
+		}
+		return expr.NewFree(FreeNames[r.IntN(len(FreeNames))])
+	}
+	if size == 2 || r.IntN(5) < 2 {
+		return expr.NewLambda(BinderNames[r.IntN(len(BinderNames))], ExprIn(r, size-1, binders+1))
+	}
+	calleeSize := 1 + uint(r.IntN(int(size)-2))
+	callee := ExprIn(r, calleeSize, binders)
+	// Redexes are rare otherwise, and they are what most properties are about
+	if r.IntN(3) == 0 && calleeSize > 1 {
+		callee = expr.NewLambda(BinderNames[r.IntN(len(BinderNames))], ExprIn(r, calleeSize-1, binders+1))
+	}
+	return expr.NewApp(callee, ExprIn(r, size-1-calleeSize, binders))
+}
+
+// Terms simpler than e, and still well-scoped if e is, simplest first
+func Shrink(e expr.Expr) iter.Seq[expr.Expr] {
+	return func(yield func(expr.Expr) bool) {
+		shrink(e, yield)
+	}
+}
+
+func shrink(e expr.Expr, yield func(expr.Expr) bool) bool {
+	if free, isFree := e.(expr.FreeVar); (!isFree || free.Name() != FreeNames[0]) && !yield(expr.NewFree(FreeNames[0])) {
+		return false
+	}
+	switch e := e.(type) {
+	case expr.BoundVar:
+		if e.Index() > 0 {
+			return yield(expr.NewBound(e.Index() - 1))
+		}
+	case expr.Lambda:
+		// The body, with its variable made free
+		if !yield(open(e.Body(), 0)) {
+			return false
+		}
+		return shrink(e.Body(), func(body expr.Expr) bool {
+			return yield(expr.NewLambda(e.ArgName(), body))
+		})
+	case expr.App:
+		if !yield(e.Callee()) || !yield(e.Arg()) {
+			return false
+		}
+		if !shrink(e.Callee(), func(callee expr.Expr) bool {
+			return yield(expr.NewApp(callee, e.Arg()))
+		}) {
+			return false
+		}
+		return shrink(e.Arg(), func(arg expr.Expr) bool {
+			return yield(expr.NewApp(e.Callee(), arg))
+		})
+	}
+	return true
+}
+
+// Replaces the variable bound just outside e with a free one
+func open(e expr.Expr, index uint) expr.Expr {
+	switch e := e.(type) {
+	case expr.BoundVar:
+		switch {
+		case e.Index() == index:
+			return expr.NewFree(FreeNames[0])
+		case e.Index() > index:
+			return expr.NewBound(e.Index() - 1)
+		}
+	case expr.Lambda:
+		return expr.NewLambda(e.ArgName(), open(e.Body(), index+1))
+	case expr.App:
+		return expr.NewApp(open(e.Callee(), index), open(e.Arg(), index))
+	}
+	return e
+}
+
+// Tries the property on terms that grow up to maxSize nodes. On failure,
+// returns the smallest counterexample shrinking could find.
+func Check(r *rand.Rand, runs int, maxSize uint, property func(e expr.Expr) bool) (expr.Expr, bool) {
+	for run := range runs {
+		size := 1 + uint(run)*maxSize/uint(max(1, runs))
+		if e := Expr(r, size); !property(e) {
+			return shrinkFailing(e, property), false
+		}
+	}
+	return nil, true
+}
+
+func shrinkFailing(e expr.Expr, property func(e expr.Expr) bool) expr.Expr {
+	for shrunk := true; shrunk; {
+		shrunk = false
+		for candidate := range Shrink(e) {
+			if !property(candidate) {
+				e, shrunk = candidate, true
+				break
+			}
+		}
+	}
+	return e
+}
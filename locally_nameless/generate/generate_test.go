@@ -0,0 +1,52 @@
+package generate
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/metrics"
+	"github.com/gusbicalho/go-lambda/locally_nameless/validate"
+)
+
+func TestExpr(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for size := uint(1); size <= 40; size++ {
+		for range 20 {
+			e := Expr(r, size)
+			if actual := metrics.Of(e).Size; actual != size {
+				t.Errorf("Expected size %d, got %d: %s", size, actual, expr.ToLambdaNotation(e, expr.DisplayBoth))
+			}
+			if errs := validate.Validate(e); len(errs) > 0 {
+				t.Errorf("%s - %s", expr.ToLambdaNotation(e, expr.DisplayBoth), errs[0])
+			}
+		}
+	}
+}
+
+func TestShrink(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 200 {
+		e := Expr(r, 12)
+		size := metrics.Of(e).Size
+		for shrunk := range Shrink(e) {
+			if metrics.Of(shrunk).Size > size {
+				t.Errorf("%s - Grew to %s", expr.ToLambdaNotation(e, expr.DisplayBoth), expr.ToLambdaNotation(shrunk, expr.DisplayBoth))
+			}
+			if errs := validate.Validate(shrunk); len(errs) > 0 {
+				t.Errorf("%s - Shrunk to %s: %s", expr.ToLambdaNotation(e, expr.DisplayBoth), expr.ToLambdaNotation(shrunk, expr.DisplayBoth), errs[0])
+			}
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	hasNoRedex := func(e expr.Expr) bool { return metrics.Of(e).Redexes == 0 }
+	counterexample, ok := Check(rand.New(rand.NewPCG(1, 2)), 100, 20, hasNoRedex)
+	if ok {
+		t.Fatal("Expected a counterexample")
+	}
+	if notation := expr.ToLambdaNotation(counterexample, expr.DisplayDeBruijn); notation != "(\\. a) a" {
+		t.Errorf("Expected the smallest redex, got %s", notation)
+	}
+}
@@ -0,0 +1,115 @@
+// Graph exports of terms, drawing an edge from each bound variable
+// to the lambda that binds it
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/metrics"
+)
+
+type Graph struct {
+	Nodes []Node
+	// Parent to child, in pre-order
+	Edges []Edge
+	// Bound variable to its binder
+	Bindings []Edge
+}
+
+type Node struct {
+	Label string
+}
+
+type Edge struct {
+	From, To uint
+	Label    string
+}
+
+func Of(e ln.Expr) Graph {
+	builder := graphBuilder{}
+	ctx := ln.EmptyContext().WithReserved(metrics.Of(e).FreeVars...)
+	builder.add(e, ctx, nil)
+	return builder.graph
+}
+
+type graphBuilder struct {
+	graph Graph
+}
+
+// Binders holds the node of each enclosing lambda, innermost last
+func (b *graphBuilder) add(e ln.Expr, ctx ln.DisplayContext, binders []uint) {
+	id := uint(len(b.graph.Nodes))
+	b.graph.Nodes = append(b.graph.Nodes, Node{})
+	switch e := e.(type) {
+	case ln.FreeVar:
+		b.graph.Nodes[id].Label = e.Name()
+	case ln.BoundVar:
+		name, found := ctx.BoundName(e.Index())
+		if !found || e.Index() >= uint(len(binders)) {
+			b.graph.Nodes[id].Label = fmt.Sprint(e.Index(), ":<outofscope>")
+			break
+		}
+		b.graph.Nodes[id].Label = fmt.Sprint(e.Index(), ":", name)
+		binder := binders[uint(len(binders))-1-e.Index()]
+		b.graph.Bindings = append(b.graph.Bindings, Edge{From: id, To: binder})
+	case ln.Lambda:
+		ctx, argName := ctx.BindFree(e.ArgName())
+		b.graph.Nodes[id].Label = "λ" + argName
+		b.child(id, "body", e.Body(), ctx, append(binders[:len(binders):len(binders)], id))
+	case ln.App:
+		b.graph.Nodes[id].Label = "@"
+		b.child(id, "callee", e.Callee(), ctx, binders)
+		b.child(id, "arg", e.Arg(), ctx, binders)
+	}
+}
+
+func (b *graphBuilder) child(parent uint, label string, e ln.Expr, ctx ln.DisplayContext, binders []uint) {
+	b.graph.Edges = append(b.graph.Edges, Edge{From: parent, To: uint(len(b.graph.Nodes)), Label: label})
+	b.add(e, ctx, binders)
+}
+
+func WriteDOT(w io.Writer, e ln.Expr) error {
+	out := bufio.NewWriter(w)
+	graph := Of(e)
+	fmt.Fprintln(out, "digraph term {")
+	fmt.Fprintln(out, "  node [fontname=\"monospace\"];")
+	for id, node := range graph.Nodes {
+		fmt.Fprintf(out, "  n%d [label=%s];\n", id, dotString(node.Label))
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(out, "  n%d -> n%d [label=%s];\n", edge.From, edge.To, dotString(edge.Label))
+	}
+	for _, edge := range graph.Bindings {
+		fmt.Fprintf(out, "  n%d -> n%d [style=dashed, color=blue, constraint=false];\n", edge.From, edge.To)
+	}
+	fmt.Fprintln(out, "}")
+	return out.Flush()
+}
+
+func WriteMermaid(w io.Writer, e ln.Expr) error {
+	out := bufio.NewWriter(w)
+	graph := Of(e)
+	fmt.Fprintln(out, "graph TD")
+	for id, node := range graph.Nodes {
+		fmt.Fprintf(out, "  n%d[\"%s\"]\n", id, mermaidString(node.Label))
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(out, "  n%d -->|%s| n%d\n", edge.From, mermaidString(edge.Label), edge.To)
+	}
+	for _, edge := range graph.Bindings {
+		fmt.Fprintf(out, "  n%d -.-> n%d\n", edge.From, edge.To)
+	}
+	return out.Flush()
+}
+
+func dotString(s string) string {
+	return "\"" + strings.NewReplacer("\\", "\\\\", "\"", "\\\"").Replace(s) + "\""
+}
+
+func mermaidString(s string) string {
+	return strings.NewReplacer("\"", "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
@@ -0,0 +1,49 @@
+package graph_test
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/graph"
+	"github.com/gusbicalho/go-lambda/test_helpers"
+)
+
+func TestBindings(t *testing.T) {
+	g := graph.Of(test_helpers.ParseExpr(t, "\\x. \\x. x (\\y. x y) z"))
+
+	labels := []string{}
+	for _, node := range g.Nodes {
+		labels = append(labels, node.Label)
+	}
+	expectedLabels := "λx λx_0 @ @ 0:x_0 λy @ 1:x_0 0:y z"
+	if strings.Join(labels, " ") != expectedLabels {
+		t.Errorf("Expected nodes %s, got %s", expectedLabels, strings.Join(labels, " "))
+	}
+
+	expectedBindings := []graph.Edge{{From: 4, To: 1}, {From: 7, To: 1}, {From: 8, To: 5}}
+	if !slices.Equal(g.Bindings, expectedBindings) {
+		t.Errorf("Expected bindings %v, got %v", expectedBindings, g.Bindings)
+	}
+}
+
+func TestWriteGolden(t *testing.T) {
+	// Shadowing, and a free variable whose name needs quoting, only possible through the API
+	e := expr.NewApp(test_helpers.ParseExpr(t, "\\x. \\x. x (\\y. x y) z"), expr.NewFree(`a"b<c>\d`))
+	cases := []struct {
+		golden string
+		write  func(io.Writer, expr.Expr) error
+	}{
+		{"dot", graph.WriteDOT},
+		{"mermaid", graph.WriteMermaid},
+	}
+	for _, c := range cases {
+		builder := strings.Builder{}
+		if err := c.write(&builder, e); err != nil {
+			t.Fatal(err)
+		}
+		test_helpers.Golden(t, c.golden, builder.String())
+	}
+}
@@ -1,7 +1,6 @@
 package hole
 
 import (
-	"fmt"
 	"slices"
 
 	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
@@ -14,12 +13,31 @@ type Hole struct {
 }
 
 func (h Hole) ToPrettyDoc(fill func(ln.DisplayContext) pretty.Doc) pretty.Doc {
-	return h.holeImpl.toPrettyDoc(ln.EmptyContext(), fill)
+	return h.holeImpl.toPrettyDoc(ln_pretty.Position{DisplayContext: ln.EmptyContext()},
+		func(pos ln_pretty.Position) pretty.Doc { return fill(pos.DisplayContext) },
+	)
+}
+
+// Subterms around the hole are elided according to options
+func (h Hole) ToPrettyDocWith(options ln_pretty.Options, fill func(ln_pretty.Position) pretty.Doc) pretty.Doc {
+	return h.holeImpl.toPrettyDoc(ln_pretty.RootPosition(ln.EmptyContext(), options), fill)
+}
+
+// Where the hole is, from the root of the term it is filled into
+func (h Hole) Path() ln.Path {
+	return h.holeImpl.path(ln.Path{})
+}
+
+// Names in scope at the hole
+func (h Hole) DisplayContext(ctx ln.DisplayContext) ln.DisplayContext {
+	return h.holeImpl.displayContext(ctx)
 }
 
 type holeImpl interface {
 	Fill(expr ln.Expr) ln.Expr
-	toPrettyDoc(ctx ln.DisplayContext, fill func(ln.DisplayContext) pretty.Doc) pretty.Doc
+	path(prefix ln.Path) ln.Path
+	displayContext(ctx ln.DisplayContext) ln.DisplayContext
+	toPrettyDoc(pos ln_pretty.Position, fill func(ln_pretty.Position) pretty.Doc) pretty.Doc
 }
 
 // Identity
@@ -55,23 +73,37 @@ func (h composeHoles) Fill(expr ln.Expr) ln.Expr {
 	return expr
 }
 
-func (h composeHoles) toPrettyDoc(ctx ln.DisplayContext, fill func(ln.DisplayContext) pretty.Doc) pretty.Doc {
-	return composeToPrettyDoc(h.holes, ctx, fill)
+func (h composeHoles) path(prefix ln.Path) ln.Path {
+	for _, hole := range h.holes {
+		prefix = hole.path(prefix)
+	}
+	return prefix
 }
 
-func composeToPrettyDoc(holes []holeImpl, ctx ln.DisplayContext, fill func(ln.DisplayContext) pretty.Doc) pretty.Doc {
+func (h composeHoles) displayContext(ctx ln.DisplayContext) ln.DisplayContext {
+	for _, hole := range h.holes {
+		ctx = hole.displayContext(ctx)
+	}
+	return ctx
+}
+
+func (h composeHoles) toPrettyDoc(pos ln_pretty.Position, fill func(ln_pretty.Position) pretty.Doc) pretty.Doc {
+	return composeToPrettyDoc(h.holes, pos, fill)
+}
+
+func composeToPrettyDoc(holes []holeImpl, pos ln_pretty.Position, fill func(ln_pretty.Position) pretty.Doc) pretty.Doc {
 	switch len(holes) {
 	case 0:
-		return fill(ctx)
+		return fill(pos)
 	case 1:
-		return holes[0].toPrettyDoc(ctx, fill)
+		return holes[0].toPrettyDoc(pos, fill)
 	}
 	hole := holes[0]
 	more := holes[1:]
-	fillMore := func(ctx ln.DisplayContext) pretty.Doc {
-		return composeToPrettyDoc(more, ctx, fill)
+	fillMore := func(pos ln_pretty.Position) pretty.Doc {
+		return composeToPrettyDoc(more, pos, fill)
 	}
-	return hole.toPrettyDoc(ctx, fillMore)
+	return hole.toPrettyDoc(pos, fillMore)
 }
 
 // Lambda
@@ -88,19 +120,18 @@ func (h lambdaBodyHole) Fill(expr ln.Expr) ln.Expr {
 	return ln.NewLambda(h.argName, expr)
 }
 
-func (h lambdaBodyHole) toPrettyDoc(ctx ln.DisplayContext, fill func(ln.DisplayContext) pretty.Doc) pretty.Doc {
-	ctx, argName := ctx.BindFree(h.argName)
-	nameLength := uint(len(argName))
-	return pretty.Sequence(
-		pretty.FromString(fmt.Sprint("λ", argName, " ─┬─")),
-		pretty.Indent(
-			nameLength+1, pretty.PrefixLines(
-				[]string{"  │ "},
-				fill(ctx),
-			),
-		),
-		pretty.Indent(nameLength+1, pretty.FromString("  ╰─")),
-	)
+func (h lambdaBodyHole) path(prefix ln.Path) ln.Path {
+	return prefix.Child(0)
+}
+
+func (h lambdaBodyHole) displayContext(ctx ln.DisplayContext) ln.DisplayContext {
+	ctx, _ = ctx.BindFree(h.argName)
+	return ctx
+}
+
+func (h lambdaBodyHole) toPrettyDoc(pos ln_pretty.Position, fill func(ln_pretty.Position) pretty.Doc) pretty.Doc {
+	body, argName := pos.LambdaBody(h.argName)
+	return pos.Mark(ln_pretty.LambdaDoc(argName, fill(body)))
 }
 
 // App: Callee
@@ -116,16 +147,24 @@ func (h appCalleeHole) Fill(expr ln.Expr) ln.Expr {
 	return ln.NewApp(expr, h.arg)
 }
 
-func (h appCalleeHole) toPrettyDoc(ctx ln.DisplayContext, fill func(ln.DisplayContext) pretty.Doc) pretty.Doc {
-	return pretty.Sequence(
-		fill(ctx),
+func (h appCalleeHole) path(prefix ln.Path) ln.Path {
+	return prefix.Child(0)
+}
+
+func (h appCalleeHole) displayContext(ctx ln.DisplayContext) ln.DisplayContext {
+	return ctx
+}
+
+func (h appCalleeHole) toPrettyDoc(pos ln_pretty.Position, fill func(ln_pretty.Position) pretty.Doc) pretty.Doc {
+	return pos.Mark(pretty.Sequence(
+		fill(pos.AppCallee()),
 		pretty.PrefixLines(
 			[]string{
 				"└► ",
 				"   ",
-			}, ln_pretty.ExprToPrettyDoc(h.arg, ctx),
+			}, ln_pretty.ExprAtToPrettyDoc(h.arg, pos.AppArg()),
 		),
-	)
+	))
 }
 
 // App: Arg
@@ -141,14 +180,22 @@ func (h appArgHole) Fill(expr ln.Expr) ln.Expr {
 	return ln.NewApp(h.callee, expr)
 }
 
-func (h appArgHole) toPrettyDoc(ctx ln.DisplayContext, fill func(ln.DisplayContext) pretty.Doc) pretty.Doc {
-	return pretty.Sequence(
-		ln_pretty.ExprToPrettyDoc(h.callee, ctx),
+func (h appArgHole) path(prefix ln.Path) ln.Path {
+	return prefix.Child(1)
+}
+
+func (h appArgHole) displayContext(ctx ln.DisplayContext) ln.DisplayContext {
+	return ctx
+}
+
+func (h appArgHole) toPrettyDoc(pos ln_pretty.Position, fill func(ln_pretty.Position) pretty.Doc) pretty.Doc {
+	return pos.Mark(pretty.Sequence(
+		ln_pretty.ExprAtToPrettyDoc(h.callee, pos.AppCallee()),
 		pretty.PrefixLines(
 			[]string{
 				"└► ",
 				"   ",
-			}, fill(ctx),
+			}, fill(pos.AppArg()),
 		),
-	)
+	))
 }
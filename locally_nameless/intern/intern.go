@@ -0,0 +1,125 @@
+// Hash-consing for locally nameless expressions.
+// Structurally equal de Bruijn terms are interned as a single *Node,
+// so two nodes from the same Table are equal iff they are the same pointer.
+// Binder names are not part of a node's identity: alpha-equivalent lambdas
+// share a node, which keeps the argName of the first one interned.
+package intern
+
+import (
+	"slices"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+)
+
+type Table struct {
+	nodes map[key]*Node
+}
+
+func NewTable() *Table {
+	return &Table{nodes: map[key]*Node{}}
+}
+
+func (t *Table) Len() int { return len(t.nodes) }
+
+type kind uint8
+
+const (
+	kindFree kind = iota
+	kindBound
+	kindLambda
+	kindApp
+)
+
+type key struct {
+	kind  kind
+	name  string
+	index uint
+	left  *Node
+	right *Node
+}
+
+type Node struct {
+	key
+	argName  string
+	expr     expr.Expr
+	size     uint
+	freeVars []string
+	redexes  uint
+}
+
+// The first term interned as this node, with its binder names
+func (n *Node) Expr() expr.Expr { return n.expr }
+
+func (n *Node) Size() uint    { return n.size }
+func (n *Node) Redexes() uint { return n.redexes }
+func (n *Node) IsRedex() bool { return n.kind == kindApp && n.left.kind == kindLambda }
+
+// Sorted names of the free variables in the term
+func (n *Node) FreeVars() []string { return slices.Clone(n.freeVars) }
+
+func (n *Node) HasFreeVar(name string) bool {
+	_, found := slices.BinarySearch(n.freeVars, name)
+	return found
+}
+
+func (t *Table) Free(name string) *Node {
+	return t.intern(key{kind: kindFree, name: name}, func(n *Node) {
+		n.expr = expr.NewFree(name)
+		n.size = 1
+		n.freeVars = []string{name}
+	})
+}
+
+func (t *Table) Bound(index uint) *Node {
+	return t.intern(key{kind: kindBound, index: index}, func(n *Node) {
+		n.expr = expr.NewBound(index)
+		n.size = 1
+	})
+}
+
+func (t *Table) Lambda(argName string, body *Node) *Node {
+	return t.intern(key{kind: kindLambda, left: body}, func(n *Node) {
+		n.argName = argName
+		n.expr = expr.NewLambda(argName, body.expr)
+		n.size = 1 + body.size
+		n.freeVars = body.freeVars
+		n.redexes = body.redexes
+	})
+}
+
+func (t *Table) App(callee, arg *Node) *Node {
+	return t.intern(key{kind: kindApp, left: callee, right: arg}, func(n *Node) {
+		n.expr = expr.NewApp(callee.expr, arg.expr)
+		n.size = 1 + callee.size + arg.size
+		n.freeVars = slices.Compact(slices.Sorted(slices.Values(slices.Concat(callee.freeVars, arg.freeVars))))
+		n.redexes = callee.redexes + arg.redexes
+		if callee.kind == kindLambda {
+			n.redexes++
+		}
+	})
+}
+
+func (t *Table) Intern(e expr.Expr) *Node {
+	return expr.CaseExpr(e, internVisit{t})
+}
+
+func (t *Table) intern(k key, init func(*Node)) *Node {
+	if n, found := t.nodes[k]; found {
+		return n
+	}
+	n := &Node{key: k}
+	init(n)
+	t.nodes[k] = n
+	return n
+}
+
+type internVisit struct{ table *Table }
+
+func (v internVisit) CaseFree(e expr.FreeVar) *Node   { return v.table.Free(e.Name()) }
+func (v internVisit) CaseBound(e expr.BoundVar) *Node { return v.table.Bound(e.Index()) }
+func (v internVisit) CaseLambda(e expr.Lambda) *Node {
+	return v.table.Lambda(e.ArgName(), v.table.Intern(e.Body()))
+}
+func (v internVisit) CaseApp(e expr.App) *Node {
+	return v.table.App(v.table.Intern(e.Callee()), v.table.Intern(e.Arg()))
+}
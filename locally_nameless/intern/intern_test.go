@@ -0,0 +1,71 @@
+package intern
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+)
+
+func TestAlphaEquivalentShareNodes(t *testing.T) {
+	table := NewTable()
+	// \x. x a, and \y. y a
+	first := table.Intern(expr.NewLambda("x", expr.NewApp(expr.NewBound(0), expr.NewFree("a"))))
+	second := table.Intern(expr.NewLambda("y", expr.NewApp(expr.NewBound(0), expr.NewFree("a"))))
+	if first != second {
+		t.Error("Expected alpha-equivalent terms to share a node")
+	}
+	if other := table.Intern(expr.NewLambda("x", expr.NewApp(expr.NewBound(0), expr.NewFree("b")))); other == first {
+		t.Error("Expected terms with different free variables to have different nodes")
+	}
+	// 0, a, 0 a, \. 0 a, b, 0 b, \. 0 b
+	if table.Len() != 7 {
+		t.Errorf("Expected 7 nodes, got %d", table.Len())
+	}
+	if notation := expr.ToLambdaNotation(second.Expr(), expr.DisplayName); notation != "\\x. x a" {
+		t.Errorf("Expected the names of the first term interned, got %s", notation)
+	}
+}
+
+func TestCachedMetrics(t *testing.T) {
+	id := expr.NewLambda("x", expr.NewBound(0))
+	cases := []struct {
+		testName string
+		e        expr.Expr
+		size     uint
+		freeVars []string
+		redexes  uint
+		isRedex  bool
+	}{
+		{"free", expr.NewFree("a"), 1, []string{"a"}, 0, false},
+		{"closed", id, 2, nil, 0, false},
+		{"redex", expr.NewApp(id, expr.NewFree("b")), 4, []string{"b"}, 1, true},
+		{
+			"nested redexes",
+			// (\x. x) ((\x. x) (b a))
+			expr.NewApp(id, expr.NewApp(id, expr.NewApp(expr.NewFree("b"), expr.NewFree("a")))),
+			9, []string{"a", "b"}, 2, true,
+		},
+		{
+			"free under binder",
+			// \x. (\x. x) (b x)
+			expr.NewLambda("x", expr.NewApp(id, expr.NewApp(expr.NewFree("b"), expr.NewBound(0)))),
+			7, []string{"b"}, 1, false,
+		},
+	}
+	for _, c := range cases {
+		n := NewTable().Intern(c.e)
+		if n.Size() != c.size || n.Redexes() != c.redexes || n.IsRedex() != c.isRedex {
+			t.Errorf("%s - Expected size %d, %d redexes, redex %v, got %d, %d, %v",
+				c.testName, c.size, c.redexes, c.isRedex, n.Size(), n.Redexes(), n.IsRedex())
+		}
+		if !slices.Equal(n.FreeVars(), c.freeVars) {
+			t.Errorf("%s - Expected free variables %v, got %v", c.testName, c.freeVars, n.FreeVars())
+		}
+		for _, name := range c.freeVars {
+			if !n.HasFreeVar(name) {
+				t.Errorf("%s - Expected free variable %s", c.testName, name)
+			}
+		}
+	}
+}
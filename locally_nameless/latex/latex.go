@@ -0,0 +1,127 @@
+// LaTeX math for terms and reduction sequences
+package latex
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
+	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/metrics"
+)
+
+type Options struct {
+	// Writes the de Bruijn index of each bound variable as a subscript
+	Indexes bool
+	// Subterm to underline, e.g. the redex of a step; nil underlines nothing
+	Underline ln.Path
+}
+
+// For math mode
+func Term(e ln.Expr, options Options) string {
+	ctx := ln.EmptyContext().WithReserved(metrics.Of(e).FreeVars...)
+	return term(e, visitLatex{ctx, options, ln.Path{}})
+}
+
+// An align* environment with a \to_\beta step for each redex, which is underlined.
+// Steps are as recorded by beta_reduce.Trace from start.
+func Derivation(start ln.Expr, steps []beta_reduce.BetaRedex, options Options) string {
+	builder := strings.Builder{}
+	builder.WriteString("\\begin{align*}\n")
+	e := start
+	for i, redex := range steps {
+		options.Underline = redex.Hole.Path()
+		if i == 0 {
+			builder.WriteString("  & ")
+		} else {
+			builder.WriteString("  \\to_\\beta & ")
+		}
+		builder.WriteString(Term(e, options))
+		builder.WriteString(" \\\\\n")
+		e = redex.Reduce()
+	}
+	options.Underline = nil
+	if len(steps) == 0 {
+		builder.WriteString("  & ")
+	} else {
+		builder.WriteString("  \\to_\\beta & ")
+	}
+	builder.WriteString(Term(e, options))
+	builder.WriteString("\n\\end{align*}\n")
+	return builder.String()
+}
+
+type visitLatex struct {
+	ctx     ln.DisplayContext
+	options Options
+	path    ln.Path
+}
+
+func term(e ln.Expr, v visitLatex) string {
+	latex := ln.CaseExpr(e, v)
+	if v.options.Underline != nil && slices.Equal(v.path, v.options.Underline) {
+		return "\\underline{" + latex + "}"
+	}
+	return latex
+}
+
+func (v visitLatex) child(index uint) visitLatex {
+	v.path = v.path.Child(index)
+	return v
+}
+
+func (v visitLatex) CaseFree(e ln.FreeVar) string {
+	return name(e.Name())
+}
+func (v visitLatex) CaseBound(e ln.BoundVar) string {
+	boundName, found := v.ctx.BoundName(e.Index())
+	if !found {
+		return fmt.Sprint("\\mathord{?}_{", e.Index(), "}")
+	}
+	if v.options.Indexes {
+		return fmt.Sprint("{", name(boundName), "}_{", e.Index(), "}")
+	}
+	return name(boundName)
+}
+func (v visitLatex) CaseLambda(e ln.Lambda) string {
+	body := v.child(0)
+	var argName string
+	body.ctx, argName = v.ctx.BindFree(e.ArgName())
+	return "\\lambda " + name(argName) + ".\\, " + term(e.Body(), body)
+}
+func (v visitLatex) CaseApp(e ln.App) string {
+	callee := term(e.Callee(), v.child(0))
+	if _, ok := e.Callee().(ln.Lambda); ok {
+		callee = "(" + callee + ")"
+	}
+	arg := term(e.Arg(), v.child(1))
+	switch e.Arg().(type) {
+	case ln.App, ln.Lambda:
+		arg = "(" + arg + ")"
+	}
+	return callee + "\\, " + arg
+}
+
+var escaper = strings.NewReplacer(
+	"\\", "\\backslash{}",
+	"{", "\\{",
+	"}", "\\}",
+	"_", "\\_",
+	"$", "\\$",
+	"&", "\\&",
+	"#", "\\#",
+	"%", "\\%",
+	"^", "\\hat{}",
+	"~", "\\sim{}",
+)
+
+// Names longer than a letter are set as one word, rather than as a product of variables
+func name(name string) string {
+	escaped := escaper.Replace(name)
+	if utf8.RuneCountInString(name) > 1 {
+		return "\\mathit{" + escaped + "}"
+	}
+	return escaped
+}
@@ -0,0 +1,30 @@
+package latex_test
+
+import (
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/latex"
+)
+
+func TestTerm(t *testing.T) {
+	// (\x. \x. x y) my_var
+	e := expr.NewApp(
+		expr.NewLambda("x", expr.NewLambda("x", expr.NewApp(expr.NewBound(0), expr.NewFree("y")))),
+		expr.NewFree("my_var"),
+	)
+	cases := []struct {
+		name     string
+		options  latex.Options
+		expected string
+	}{
+		{"names", latex.Options{}, `(\lambda x.\, \lambda \mathit{x\_0}.\, \mathit{x\_0}\, y)\, \mathit{my\_var}`},
+		{"indexes", latex.Options{Indexes: true}, `(\lambda x.\, \lambda \mathit{x\_0}.\, {\mathit{x\_0}}_{0}\, y)\, \mathit{my\_var}`},
+		{"underline", latex.Options{Underline: expr.Path{0, 0}}, `(\lambda x.\, \underline{\lambda \mathit{x\_0}.\, \mathit{x\_0}\, y})\, \mathit{my\_var}`},
+	}
+	for _, c := range cases {
+		if actual := latex.Term(e, c.options); actual != c.expected {
+			t.Errorf("%s - Expected: %s\nActual:   %s", c.name, c.expected, actual)
+		}
+	}
+}
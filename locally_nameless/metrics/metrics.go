@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+)
+
+type Metrics struct {
+	Size    uint
+	Depth   uint
+	Lambdas uint
+	// Sorted, without duplicates
+	FreeVars []string
+	// -1 when the term has no bound variables
+	MaxIndex int
+	Redexes  uint
+}
+
+func Of(e expr.Expr) Metrics {
+	return expr.CaseExpr(e, visitMetrics{})
+}
+
+func (m Metrics) String() string {
+	return fmt.Sprint(
+		"size ", m.Size,
+		" · depth ", m.Depth,
+		" · λ ", m.Lambdas,
+		" · free {", strings.Join(m.FreeVars, ", "), "}",
+		" · max index ", m.MaxIndex,
+		" · redexes ", m.Redexes,
+	)
+}
+
+type visitMetrics struct{}
+
+func (v visitMetrics) CaseFree(e expr.FreeVar) Metrics {
+	return Metrics{Size: 1, Depth: 1, FreeVars: []string{e.Name()}, MaxIndex: -1}
+}
+
+func (v visitMetrics) CaseBound(e expr.BoundVar) Metrics {
+	return Metrics{Size: 1, Depth: 1, MaxIndex: int(e.Index())}
+}
+
+func (v visitMetrics) CaseLambda(e expr.Lambda) Metrics {
+	m := Of(e.Body())
+	m.Size++
+	m.Depth++
+	m.Lambdas++
+	return m
+}
+
+func (v visitMetrics) CaseApp(e expr.App) Metrics {
+	callee := Of(e.Callee())
+	arg := Of(e.Arg())
+	m := Metrics{
+		Size:     1 + callee.Size + arg.Size,
+		Depth:    1 + max(callee.Depth, arg.Depth),
+		Lambdas:  callee.Lambdas + arg.Lambdas,
+		FreeVars: slices.Compact(slices.Sorted(slices.Values(slices.Concat(callee.FreeVars, arg.FreeVars)))),
+		MaxIndex: max(callee.MaxIndex, arg.MaxIndex),
+		Redexes:  callee.Redexes + arg.Redexes,
+	}
+	if _, isLambda := e.Callee().(expr.Lambda); isLambda {
+		m.Redexes++
+	}
+	return m
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+)
+
+func TestOf(t *testing.T) {
+	id := expr.NewLambda("x", expr.NewBound(0))
+	cases := []struct {
+		testName string
+		e        expr.Expr
+		expected Metrics
+	}{
+		{"free variable", expr.NewFree("a"), Metrics{Size: 1, Depth: 1, FreeVars: []string{"a"}, MaxIndex: -1}},
+		{"out of scope bound variable", expr.NewBound(2), Metrics{Size: 1, Depth: 1, MaxIndex: 2}},
+		{"closed", id, Metrics{Size: 2, Depth: 2, Lambdas: 1, MaxIndex: 0}},
+		{
+			"closed, nested binders",
+			// \x. \y. x y
+			expr.NewLambda("x", expr.NewLambda("y", expr.NewApp(expr.NewBound(1), expr.NewBound(0)))),
+			Metrics{Size: 5, Depth: 4, Lambdas: 2, MaxIndex: 1},
+		},
+		{
+			"open, repeated free variables",
+			// b (\x. a x) b
+			expr.NewApp(expr.NewApp(expr.NewFree("b"), expr.NewLambda("x", expr.NewApp(expr.NewFree("a"), expr.NewBound(0)))), expr.NewFree("b")),
+			Metrics{Size: 8, Depth: 5, Lambdas: 1, FreeVars: []string{"a", "b"}, MaxIndex: 0},
+		},
+		{
+			"nested redexes",
+			// (\x. x) ((\x. x) ((\y. y) c))
+			expr.NewApp(id, expr.NewApp(id, expr.NewApp(expr.NewLambda("y", expr.NewBound(0)), expr.NewFree("c")))),
+			Metrics{Size: 10, Depth: 5, Lambdas: 3, FreeVars: []string{"c"}, MaxIndex: 0, Redexes: 3},
+		},
+		{
+			"redex under a binder, in the callee",
+			// (\f. (\x. x) f) g
+			expr.NewApp(expr.NewLambda("f", expr.NewApp(id, expr.NewBound(0))), expr.NewFree("g")),
+			Metrics{Size: 7, Depth: 5, Lambdas: 2, FreeVars: []string{"g"}, MaxIndex: 0, Redexes: 2},
+		},
+	}
+	for _, c := range cases {
+		actual := Of(c.e)
+		if !slices.Equal(actual.FreeVars, c.expected.FreeVars) ||
+			actual.Size != c.expected.Size || actual.Depth != c.expected.Depth || actual.Lambdas != c.expected.Lambdas ||
+			actual.MaxIndex != c.expected.MaxIndex || actual.Redexes != c.expected.Redexes {
+			t.Errorf("%s - Expected %+v, got %+v", c.testName, c.expected, actual)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	e := expr.NewApp(expr.NewLambda("x", expr.NewBound(0)), expr.NewFree("a"))
+	expected := "size 4 · depth 3 · λ 1 · free {a} · max index 0 · redexes 1"
+	if actual := Of(e).String(); actual != expected {
+		t.Errorf("Expected: %s, Actual: %s", expected, actual)
+	}
+}
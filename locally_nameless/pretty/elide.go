@@ -0,0 +1,184 @@
+package pretty
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/metrics"
+	"github.com/gusbicalho/go-lambda/pretty"
+)
+
+// Limits for rendering huge terms. Compound subterms past any of them are
+// elided as …[size]. Zero means unlimited.
+type Options struct {
+	// Nesting depth, counted from the root or from an expanded subterm
+	MaxDepth uint
+	// Nodes rendered, in pre-order, from the root or from an expanded subterm
+	MaxNodes uint
+	// Column where a subterm starts in the tree layout; other layouts ignore it
+	MaxWidth uint
+	// Subterms that are rendered with fresh limits. Their ancestors are never elided.
+	Expanded []ln.Path
+	// Subterms wrapped in an annotation
+	Marks []Mark
+	// Wraps every subterm in a region, identified by RegionID of its path
+	Regions bool
+}
+
+type Mark struct {
+	Path       ln.Path
+	Annotation pretty.Annotation
+}
+
+// Where a subterm is rendered: the names in scope, its path and
+// how much is left of the limits. The zero value has no limits.
+type Position struct {
+	ln.DisplayContext
+	options *Options
+	path    ln.Path
+	depth   uint
+	column  uint
+	nodes   *uint
+}
+
+func RootPosition(ctx ln.DisplayContext, options Options) Position {
+	return Position{
+		DisplayContext: ctx,
+		options:        &options,
+		path:           ln.Path{},
+		nodes:          new(uint),
+	}
+}
+
+func (pos Position) Path() ln.Path {
+	return pos.path
+}
+
+// The same position, with other marks, matched against Path
+func (pos Position) WithMarks(marks ...Mark) Position {
+	options := Options{}
+	if pos.options != nil {
+		options = *pos.options
+	}
+	options.Marks = marks
+	pos.options = &options
+	if pos.nodes == nil {
+		pos.nodes = new(uint)
+	}
+	return pos
+}
+
+func (pos Position) LambdaBody(argName string) (Position, string) {
+	ctx, argName := pos.DisplayContext.BindFree(argName)
+	pos.DisplayContext = ctx
+	return pos.child(0, pretty.Width(argName)+5), argName
+}
+
+func (pos Position) AppCallee() Position {
+	return pos.child(0, 0)
+}
+
+func (pos Position) AppArg() Position {
+	return pos.child(1, 3)
+}
+
+func (pos Position) child(index uint, indent uint) Position {
+	if pos.options == nil {
+		return pos
+	}
+	pos.path = pos.path.Child(index)
+	pos.depth++
+	pos.column += indent
+	return pos
+}
+
+// Counts the node at pos, restarting the limits if it is expanded.
+// Returns the doc to show instead when it is past a limit.
+// Only the tree layout has the columns pos keeps, so only it checks MaxWidth.
+func (pos Position) enter(e ln.Expr, layout Layout) (Position, *pretty.Doc) {
+	options := pos.options
+	if options == nil {
+		return pos, nil
+	}
+	onExpandedPath := false
+	for _, expanded := range options.Expanded {
+		if expanded.HasPrefix(pos.path) {
+			onExpandedPath = true
+			if len(expanded) == len(pos.path) {
+				pos.depth = 0
+				pos.column = 0
+				pos.nodes = new(uint)
+			}
+		}
+	}
+	switch e.(type) {
+	case ln.Lambda, ln.App:
+		if !onExpandedPath &&
+			(options.MaxDepth > 0 && pos.depth >= options.MaxDepth ||
+				options.MaxNodes > 0 && *pos.nodes >= options.MaxNodes ||
+				layout == LayoutTree && options.MaxWidth > 0 && pos.column >= options.MaxWidth) {
+			doc := ElidedDoc(metrics.Of(e).Size)
+			return pos, &doc
+		}
+	}
+	*pos.nodes++
+	return pos, nil
+}
+
+// Wraps the doc of the subterm at pos in its region and in the annotations marking it
+func (pos Position) Mark(doc pretty.Doc) pretty.Doc {
+	for _, mark := range pos.marks() {
+		doc = pretty.Annotate(mark.Annotation, doc)
+	}
+	if pos.options != nil && pos.options.Regions {
+		doc = pretty.Annotate(pretty.RegionAnnotation(RegionID(pos.path)), doc)
+	}
+	return doc
+}
+
+func (pos Position) marks() []Mark {
+	if pos.options == nil {
+		return nil
+	}
+	marks := []Mark{}
+	for _, mark := range pos.options.Marks {
+		if slices.Equal(mark.Path, pos.path) {
+			marks = append(marks, mark)
+		}
+	}
+	return marks
+}
+
+// Region IDs are restricted, so /0/1 becomes n.0.1
+func RegionID(path ln.Path) string {
+	builder := strings.Builder{}
+	builder.WriteString("n")
+	for _, child := range path {
+		builder.WriteString(".")
+		builder.WriteString(fmt.Sprint(child))
+	}
+	return builder.String()
+}
+
+func ParseRegionID(id string) (ln.Path, bool) {
+	parts := strings.Split(id, ".")
+	if parts[0] != "n" {
+		return nil, false
+	}
+	path := ln.Path{}
+	for _, part := range parts[1:] {
+		child, err := strconv.ParseUint(part, 10, 0)
+		if err != nil {
+			return nil, false
+		}
+		path = append(path, uint(child))
+	}
+	return path, true
+}
+
+func ElidedDoc(size uint) pretty.Doc {
+	return pretty.Annotate(pretty.Elided, pretty.FromString(fmt.Sprint("…[", size, "]")))
+}
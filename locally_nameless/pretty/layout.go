@@ -0,0 +1,103 @@
+package pretty
+
+import (
+	"errors"
+	"fmt"
+
+	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/pretty"
+)
+
+type Layout uint
+
+const (
+	// Box-drawing tree, λx ─┬─ and └►
+	LayoutTree Layout = iota
+	// Lambda notation with names
+	LayoutNotation
+	// (lam x (app x y))
+	LayoutSExpr
+	// Lambda notation with indexes only
+	LayoutDeBruijn
+)
+
+var layoutNames = []string{"tree", "notation", "sexpr", "debruijn"}
+
+func (layout Layout) String() string {
+	return layoutNames[layout]
+}
+
+func ParseLayout(name string) (Layout, error) {
+	for i, layoutName := range layoutNames {
+		if name == layoutName {
+			return Layout(i), nil
+		}
+	}
+	return 0, errors.New(fmt.Sprint("Unknown layout ", name, ", expected one of ", layoutNames))
+}
+
+func ToLayoutDoc(expr ln.Expr, layout Layout, options Options) pretty.Doc {
+	switch layout {
+	case LayoutNotation:
+		return ToNotationDocWith(expr, ln.DisplayName, options)
+	case LayoutSExpr:
+		return ToSExprDocWith(expr, options)
+	case LayoutDeBruijn:
+		return ToNotationDocWith(expr, ln.DisplayDeBruijn, options)
+	default:
+		return ToPrettyDocWith(expr, options)
+	}
+}
+
+func ToSExprDoc(expr ln.Expr) pretty.Doc {
+	return ToSExprDocWith(expr, Options{})
+}
+
+func ToSExprDocWith(expr ln.Expr, options Options) pretty.Doc {
+	ctx := ln.EmptyContext().WithDisplayBoundVarAs(ln.DisplayName)
+	return ExprAtToSExprDoc(expr, RootPosition(ctx, options))
+}
+
+func ExprAtToSExprDoc(expr ln.Expr, pos Position) pretty.Doc {
+	pos, elided := pos.enter(expr, LayoutSExpr)
+	if elided != nil {
+		return pos.Mark(*elided)
+	}
+	return pos.Mark(ln.CaseExpr(expr, visitSExpr{pos}))
+}
+
+type visitSExpr struct{ Position }
+
+func (v visitSExpr) CaseFree(expr ln.FreeVar) pretty.Doc {
+	return visitPretty(v).CaseFree(expr)
+}
+func (v visitSExpr) CaseBound(expr ln.BoundVar) pretty.Doc {
+	return visitPretty(v).CaseBound(expr)
+}
+func (v visitSExpr) CaseLambda(expr ln.Lambda) pretty.Doc {
+	pos, argName := v.Position.LambdaBody(expr.ArgName())
+	return sexpr(
+		pretty.Concat(pretty.FromString("lam "), pretty.Annotate(pretty.Binder, pretty.FromString(argName))),
+		ExprAtToSExprDoc(expr.Body(), pos),
+	)
+}
+func (v visitSExpr) CaseApp(expr ln.App) pretty.Doc {
+	return sexpr(
+		pretty.FromString("app"),
+		ExprAtToSExprDoc(expr.Callee(), v.Position.AppCallee()),
+		ExprAtToSExprDoc(expr.Arg(), v.Position.AppArg()),
+	)
+}
+
+func sexpr(head pretty.Doc, items ...pretty.Doc) pretty.Doc {
+	rest := []pretty.Doc{}
+	for _, item := range items {
+		rest = append(rest, pretty.Line(), item)
+	}
+	return pretty.Group(pretty.Concat(
+		pretty.FromString("("),
+		head,
+		pretty.Nest(2, pretty.Concat(rest...)),
+		pretty.FromString(")"),
+	))
+}
@@ -1,7 +1,7 @@
 package pretty
 
 import (
-	"fmt"
+	"slices"
 	"strings"
 
 	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
@@ -12,39 +12,174 @@ func ToPrettyDoc(expr ln.Expr) pretty.Doc {
 	return ExprToPrettyDoc(expr, ln.EmptyContext())
 }
 
+func ToPrettyDocWith(expr ln.Expr, options Options) pretty.Doc {
+	return ExprAtToPrettyDoc(expr, RootPosition(ln.EmptyContext(), options))
+}
+
 func ExprToPrettyDoc(expr ln.Expr, ctx ln.DisplayContext) pretty.Doc {
-	return ln.CaseExpr(expr, visitPretty{ctx})
+	return ExprAtToPrettyDoc(expr, Position{DisplayContext: ctx})
 }
 
-type visitPretty struct{ ln.DisplayContext }
+func ExprAtToPrettyDoc(expr ln.Expr, pos Position) pretty.Doc {
+	pos, elided := pos.enter(expr, LayoutTree)
+	if elided != nil {
+		return pos.Mark(*elided)
+	}
+	return pos.Mark(ln.CaseExpr(expr, visitPretty{pos}))
+}
+
+type visitPretty struct{ Position }
 
 func (v visitPretty) CaseFree(expr ln.FreeVar) pretty.Doc {
-	return pretty.FromString(expr.Name())
+	return pretty.Annotate(pretty.FreeVar, pretty.FromString(expr.Name()))
 }
 func (v visitPretty) CaseBound(expr ln.BoundVar) pretty.Doc {
 	builder := strings.Builder{}
 	if err := expr.WriteLambdaNotation(v.DisplayContext, &builder); err != nil {
 		panic(err)
 	}
-	return pretty.FromString(builder.String())
+	return pretty.Annotate(pretty.BoundVar, pretty.FromString(builder.String()))
 }
 func (v visitPretty) CaseLambda(expr ln.Lambda) pretty.Doc {
-	ctx, argName := v.DisplayContext.BindFree(expr.ArgName())
-	nameLength := uint(len(argName))
+	pos, argName := v.Position.LambdaBody(expr.ArgName())
+	return LambdaDoc(argName, ExprAtToPrettyDoc(expr.Body(), pos))
+}
+
+func LambdaDoc(argName string, body pretty.Doc) pretty.Doc {
+	nameLength := pretty.Width(argName)
 	return pretty.Sequence(
-		pretty.FromString(fmt.Sprint("λ", argName, " ─┬─")),
-		pretty.Indent(nameLength+1, pretty.PrefixLines([]string{"  │ "},
-			ExprToPrettyDoc(expr.Body(), ctx),
-		)),
+		pretty.Concat(
+			pretty.FromString("λ"),
+			pretty.Annotate(pretty.Binder, pretty.FromString(argName)),
+			pretty.FromString(" ─┬─"),
+		),
+		pretty.Indent(nameLength+1, pretty.PrefixLines([]string{"  │ "}, body)),
 		pretty.Indent(nameLength+1, pretty.FromString("  ╰─")),
 	)
 }
 func (v visitPretty) CaseApp(expr ln.App) pretty.Doc {
 	return pretty.Sequence(
-		ExprToPrettyDoc(expr.Callee(), v.DisplayContext),
+		ExprAtToPrettyDoc(expr.Callee(), v.Position.AppCallee()),
 		pretty.PrefixLines([]string{
 			"└► ",
 			"   ",
-		}, ExprToPrettyDoc(expr.Arg(), v.DisplayContext)),
+		}, ExprAtToPrettyDoc(expr.Arg(), v.Position.AppArg())),
 	)
 }
+
+// Lambda notation, broken over several lines where it does not fit the width
+func ToNotationDoc(expr ln.Expr, displayBoundVarAs ln.DisplayBoundVarAs) pretty.Doc {
+	return ExprToNotationDoc(expr, ln.EmptyContext().WithDisplayBoundVarAs(displayBoundVarAs))
+}
+
+func ToNotationDocWith(expr ln.Expr, displayBoundVarAs ln.DisplayBoundVarAs, options Options) pretty.Doc {
+	ctx := ln.EmptyContext().WithDisplayBoundVarAs(displayBoundVarAs)
+	return ExprAtToNotationDoc(expr, RootPosition(ctx, options))
+}
+
+func ExprToNotationDoc(expr ln.Expr, ctx ln.DisplayContext) pretty.Doc {
+	return ExprAtToNotationDoc(expr, Position{DisplayContext: ctx})
+}
+
+func ExprAtToNotationDoc(expr ln.Expr, pos Position) pretty.Doc {
+	pos, elided := pos.enter(expr, LayoutNotation)
+	if elided != nil {
+		return pos.Mark(*elided)
+	}
+	return pos.Mark(ln.CaseExpr(expr, visitNotation{pos}))
+}
+
+type visitNotation struct{ Position }
+
+func (v visitNotation) CaseFree(expr ln.FreeVar) pretty.Doc {
+	return visitPretty(v).CaseFree(expr)
+}
+func (v visitNotation) CaseBound(expr ln.BoundVar) pretty.Doc {
+	return visitPretty(v).CaseBound(expr)
+}
+func (v visitNotation) CaseLambda(expr ln.Lambda) pretty.Doc {
+	pos := v.Position
+	binders := []pretty.Doc{}
+	var body pretty.Doc
+	for lambda := expr; ; {
+		var argName string
+		pos, argName = pos.LambdaBody(lambda.ArgName())
+		if len(binders) > 0 {
+			binders = append(binders, pretty.FromString(" "))
+		}
+		if pos.DisplayBoundVarAs() == ln.DisplayDeBruijn {
+			binders = append(binders, pretty.FromString("\\."))
+		} else {
+			binders = append(binders,
+				pretty.FromString("\\"),
+				pretty.Annotate(pretty.Binder, pretty.FromString(argName)),
+				pretty.FromString("."),
+			)
+		}
+		next, ok := lambda.Body().(ln.Lambda)
+		if !ok {
+			body = ExprAtToNotationDoc(lambda.Body(), pos)
+			break
+		}
+		var elided *pretty.Doc
+		if pos, elided = pos.enter(next, LayoutNotation); elided != nil {
+			body = pos.Mark(*elided)
+			break
+		}
+		if len(pos.marks()) > 0 {
+			body = pos.Mark(ln.CaseExpr(next, visitNotation{pos}))
+			break
+		}
+		lambda = next
+	}
+	return pretty.Group(pretty.Concat(
+		pretty.Concat(binders...),
+		pretty.Nest(2, pretty.Concat(pretty.Line(), body)),
+	))
+}
+func (v visitNotation) CaseApp(expr ln.App) pretty.Doc {
+	spine := []ln.App{expr}
+	positions := []Position{v.Position}
+	var calleeDoc pretty.Doc
+	for {
+		last := len(spine) - 1
+		callee := spine[last].Callee()
+		pos, elided := positions[last].AppCallee().enter(callee, LayoutNotation)
+		if elided != nil {
+			calleeDoc = pos.Mark(*elided)
+			break
+		}
+		if app, ok := callee.(ln.App); ok && len(pos.marks()) == 0 {
+			spine = append(spine, app)
+			positions = append(positions, pos)
+			continue
+		}
+		calleeDoc = ln.CaseExpr(callee, visitNotation{pos})
+		if _, ok := callee.(ln.Lambda); ok {
+			calleeDoc = parens(calleeDoc)
+		}
+		calleeDoc = pos.Mark(calleeDoc)
+		break
+	}
+
+	args := []pretty.Doc{}
+	for i, app := range slices.Backward(spine) {
+		pos, elided := positions[i].AppArg().enter(app.Arg(), LayoutNotation)
+		var arg pretty.Doc
+		if elided != nil {
+			arg = *elided
+		} else {
+			arg = ln.CaseExpr(app.Arg(), visitNotation{pos})
+			switch app.Arg().(type) {
+			case ln.App, ln.Lambda:
+				arg = parens(arg)
+			}
+		}
+		args = append(args, pretty.Line(), pos.Mark(arg))
+	}
+	return pretty.Group(pretty.Concat(calleeDoc, pretty.Nest(2, pretty.Concat(args...))))
+}
+
+func parens(doc pretty.Doc) pretty.Doc {
+	return pretty.Concat(pretty.FromString("("), pretty.Nest(1, doc), pretty.FromString(")"))
+}
@@ -0,0 +1,94 @@
+package pretty_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	ln_pretty "github.com/gusbicalho/go-lambda/locally_nameless/pretty"
+	"github.com/gusbicalho/go-lambda/test_helpers"
+)
+
+func render(t *testing.T, source string) string {
+	e := test_helpers.ParseExpr(t, source)
+
+	builder := strings.Builder{}
+	builder.WriteString("== source\n")
+	builder.WriteString(source)
+	builder.WriteString("\n== tree\n")
+	builder.WriteString(ln_pretty.ToPrettyDoc(e).String())
+	builder.WriteString("\n== notation, width 12\n")
+	builder.WriteString(ln_pretty.ToNotationDoc(e, expr.DisplayName).Pretty(12))
+	for redex := range beta_reduce.BetaRedexes(e) {
+		builder.WriteString("\n== redex\n")
+		builder.WriteString(redex.ToPrettyDoc(nil).String())
+	}
+	builder.WriteString("\n")
+	return builder.String()
+}
+
+func TestUnicodeGolden(t *testing.T) {
+	cases := []struct {
+		golden string
+		source string
+	}{
+		{"greek", "(\\α. \\β. α β) (\\γ. γ)"},
+		{"cjk", "\\函数. \\参数. 函数 (函数 参数)"},
+		{"combining", "(\\cafe\u0301. \\e\u0301. cafe\u0301 e\u0301) x"},
+		{"lambda_names", "\\λ. λ (\\λ. λ)"},
+		{"wide_shadowing", "\\名. (\\名. 名) 名"},
+	}
+	for _, c := range cases {
+		test_helpers.Golden(t, c.golden, render(t, c.source))
+	}
+}
+
+func TestElision(t *testing.T) {
+	source := "\\f. \\x. f (f (f (f x))) (\\y. y y)"
+	e := test_helpers.ParseExpr(t, source)
+
+	cases := []struct {
+		name     string
+		options  ln_pretty.Options
+		expected string
+	}{
+		{"unlimited", ln_pretty.Options{}, "\\f. \\x. f (f (f (f x))) (\\y. y y)"},
+		{"depth", ln_pretty.Options{MaxDepth: 4}, "\\f. \\x. f …[7] (\\y. …[3])"},
+		{"nodes", ln_pretty.Options{MaxNodes: 6}, "\\f. \\x. f (f …[5]) …[4]"},
+		{"expanded", ln_pretty.Options{MaxDepth: 4, Expanded: []expr.Path{{0, 0, 0, 1, 1}}}, "\\f. \\x. f (f (f (f x))) (\\y. …[3])"},
+	}
+	for _, c := range cases {
+		actual := ln_pretty.ToNotationDocWith(e, expr.DisplayName, c.options).String()
+		if actual != c.expected {
+			t.Errorf("%s - Expected: %s, Actual: %s", c.name, c.expected, actual)
+		}
+	}
+}
+
+func TestLayoutGolden(t *testing.T) {
+	// Shadowing and a free variable, nested deep enough for parens in every layout
+	e := test_helpers.ParseExpr(t, "\\x. \\x. x (\\y. x y) z (f \\w. w)")
+	for _, layout := range []ln_pretty.Layout{ln_pretty.LayoutSExpr, ln_pretty.LayoutDeBruijn} {
+		test_helpers.Golden(t, layout.String(), ln_pretty.ToLayoutDoc(e, layout, ln_pretty.Options{}).String()+"\n")
+	}
+}
+
+func TestWidthElision(t *testing.T) {
+	source := "\\f. \\x. f (f (f x))"
+	e := test_helpers.ParseExpr(t, source)
+	options := ln_pretty.Options{MaxWidth: 13}
+	if actual := ln_pretty.ToNotationDocWith(e, expr.DisplayName, options).String(); actual != source {
+		t.Errorf("Expected the notation to ignore the width, got %s", actual)
+	}
+	// The argument of the application starts past column 13
+	expected := "λf ─┬─\n" +
+		"    │ λx ─┬─\n" +
+		"    │     │ 1:f\n" +
+		"    │     │ └► …[5]\n" +
+		"    │     ╰─\n" +
+		"    ╰─"
+	if actual := ln_pretty.ToPrettyDocWith(e, options).String(); actual != expected {
+		t.Errorf("Expected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
@@ -0,0 +1,167 @@
+// Refactorings for equational reasoning. Each one rewrites a term into
+// a beta-equivalent one, around a focused subterm.
+package rewrite
+
+import (
+	"errors"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
+	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/walk"
+)
+
+// Returns the rewritten term, and the path of the subterm to focus in it
+type Rewrite func(focus walk.Focus) (ln.Expr, ln.Path, error)
+
+// Beta-expansion: the focus becomes the argument of a new redex, (\name. ...focus...) focus.
+// The redex is placed as high as the focus can go without leaving the scope of its binders.
+func Extract(name string) Rewrite {
+	return func(focus walk.Focus) (ln.Expr, ln.Path, error) {
+		return abstract(focus, name, false)
+	}
+}
+
+// Like Extract, but every alpha-equivalent occurrence of the focus in the new
+// lambda's body is replaced by the new variable
+func Abstract(name string) Rewrite {
+	return func(focus walk.Focus) (ln.Expr, ln.Path, error) {
+		return abstract(focus, name, true)
+	}
+}
+
+// On a variable bound by a let, (\x. ...x...) v, replaces it with v.
+// On the let itself, replaces every occurrence and removes the binder.
+func Inline() Rewrite {
+	return func(focus walk.Focus) (ln.Expr, ln.Path, error) {
+		path := focus.Hole.Path()
+		if redex := beta_reduce.AsBetaRedex(focus.Expr); redex != nil {
+			return focus.Hole.Fill(redex.Reduce()), path, nil
+		}
+		variable, ok := focus.Expr.(ln.BoundVar)
+		if !ok {
+			return nil, nil, errors.New("Only variables and lets can be inlined")
+		}
+		term := focus.Realize()
+		binders := bindersAlong(term, path)
+		if int(variable.Index()) >= len(binders) {
+			return nil, nil, errors.New("The variable is not bound in the term")
+		}
+		binderPath := binders[len(binders)-1-int(variable.Index())]
+		notLet := errors.New("The variable is not bound by a let, (\\x. ...) v")
+		if len(binderPath) == 0 || binderPath[len(binderPath)-1] != 0 {
+			return nil, nil, notLet
+		}
+		let, _ := ln.At(term, binderPath[:len(binderPath)-1])
+		app, ok := let.(ln.App)
+		if !ok {
+			return nil, nil, notLet
+		}
+		// The value moves under the binders between the let and the variable
+		return focus.Hole.Fill(beta_reduce.Shift(app.Arg(), int(variable.Index())+1, 0)), path, nil
+	}
+}
+
+// Changes only the name a lambda shows for its variable
+func Rename(name string) Rewrite {
+	return func(focus walk.Focus) (ln.Expr, ln.Path, error) {
+		lambda, ok := focus.Expr.(ln.Lambda)
+		if !ok {
+			return nil, nil, errors.New("Only lambdas can be renamed")
+		}
+		if name == "" {
+			return nil, nil, errors.New("The new name is empty")
+		}
+		return focus.Hole.Fill(ln.NewLambda(name, lambda.Body())), focus.Hole.Path(), nil
+	}
+}
+
+func abstract(focus walk.Focus, name string, all bool) (ln.Expr, ln.Path, error) {
+	term := focus.Realize()
+	path := focus.Hole.Path()
+	binders := bindersAlong(term, path)
+
+	// The new redex goes in the body of the innermost lambda the focus refers to
+	scope := ln.Path{}
+	under := uint(len(binders))
+	if innermost, ok := innermostEscaping(focus.Expr, 0); ok {
+		scope = binders[len(binders)-1-int(innermost)].Child(0)
+		under = innermost
+	}
+	value := beta_reduce.Shift(focus.Expr, -int(under), 0)
+
+	nav, _ := walk.NavTo(term, scope).UpdateExpr(func(body ln.Expr) *ln.Expr {
+		body = replace(body, value, path[len(scope):], true, all, 0)
+		var redex ln.Expr = ln.NewApp(ln.NewLambda(name, body), value)
+		return &redex
+	})
+	return nav.Focus().Realize(), scope, nil
+}
+
+// Paths to the lambdas above the end of the path, outermost first
+func bindersAlong(term ln.Expr, path ln.Path) []ln.Path {
+	binders := []ln.Path{}
+	for i := range path {
+		if e, _ := ln.At(term, path[:i]); e != nil {
+			if _, ok := e.(ln.Lambda); ok {
+				binders = append(binders, path[:i])
+			}
+		}
+	}
+	return binders
+}
+
+// The smallest index of a variable bound outside e, seen from the root of e
+func innermostEscaping(e ln.Expr, depth uint) (uint, bool) {
+	switch e := e.(type) {
+	case ln.BoundVar:
+		if e.Index() >= depth {
+			return e.Index() - depth, true
+		}
+	case ln.Lambda:
+		return innermostEscaping(e.Body(), depth+1)
+	case ln.App:
+		callee, calleeOk := innermostEscaping(e.Callee(), depth)
+		arg, argOk := innermostEscaping(e.Arg(), depth)
+		switch {
+		case calleeOk && argOk:
+			return min(callee, arg), true
+		case calleeOk:
+			return callee, true
+		case argOk:
+			return arg, true
+		}
+	}
+	return 0, false
+}
+
+// Replaces the subterm at path, and other occurrences of the value when all is set,
+// with the variable bound just outside e. Variables bound further out are shifted
+// past that new binder. Off the path to the focus, onPath is false.
+func replace(e ln.Expr, value ln.Expr, path ln.Path, onPath bool, all bool, depth uint) ln.Expr {
+	if onPath && len(path) == 0 || all && ln.AlphaEqual(e, beta_reduce.Shift(value, int(depth), 0)) {
+		return ln.NewBound(depth)
+	}
+	child := func(index uint) (ln.Path, bool) {
+		if onPath && path[0] == index {
+			return path[1:], true
+		}
+		return nil, false
+	}
+	switch e := e.(type) {
+	case ln.BoundVar:
+		if e.Index() >= depth {
+			return ln.NewBound(e.Index() + 1)
+		}
+	case ln.Lambda:
+		bodyPath, bodyOnPath := child(0)
+		return ln.NewLambda(e.ArgName(), replace(e.Body(), value, bodyPath, bodyOnPath, all, depth+1))
+	case ln.App:
+		calleePath, calleeOnPath := child(0)
+		argPath, argOnPath := child(1)
+		return ln.NewApp(
+			replace(e.Callee(), value, calleePath, calleeOnPath, all, depth),
+			replace(e.Arg(), value, argPath, argOnPath, all, depth),
+		)
+	}
+	return e
+}
@@ -0,0 +1,75 @@
+package rewrite
+
+import (
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
+	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/walk"
+	"github.com/gusbicalho/go-lambda/test_helpers"
+)
+
+func TestRewrites(t *testing.T) {
+	cases := []struct {
+		testName string
+		source   string
+		path     ln.Path
+		rewrite  Rewrite
+		expected string
+	}{
+		{"extract closed", "\\x. f (g y) x", ln.Path{0, 0, 1}, Extract("v"), "(\\v. \\x. f v x) (g y)"},
+		{"extract in scope", "\\x. \\y. f (g x) y", ln.Path{0, 0, 0, 1}, Extract("v"), "\\x. (\\v. \\y. f v y) (g x)"},
+		{"extract one", "f (g y) (g y)", ln.Path{0, 1}, Extract("v"), "(\\v. f v (g y)) (g y)"},
+		{"abstract all", "f (g y) (g y)", ln.Path{0, 1}, Abstract("v"), "(\\v. f v v) (g y)"},
+		{"abstract under binder", "\\x. g x (\\z. g x)", ln.Path{0, 0}, Abstract("v"), "\\x. (\\v. v (\\z. v)) (g x)"},
+		{"inline variable", "(\\x. f x (\\y. x)) a", ln.Path{0, 0, 0, 1}, Inline(), "(\\x. f a (\\y. x)) a"},
+		{"inline under binder", "(\\x. \\y. x) (\\z. z)", ln.Path{0, 0, 0}, Inline(), "(\\x. \\y. \\z. z) (\\z. z)"},
+		{"inline let", "(\\x. f x x) a", ln.Path{}, Inline(), "f a a"},
+		{"rename", "(\\x. f x x) a", ln.Path{0}, Rename("y"), "(\\y. f y y) a"},
+	}
+	for _, c := range cases {
+		e := test_helpers.ParseExpr(t, c.source)
+		actual, _, err := c.rewrite(walk.NavTo(e, c.path).Focus())
+		if err != nil {
+			t.Errorf("%s - %s", c.testName, err)
+			continue
+		}
+		if notation := ln.ToLambdaNotation(actual, ln.DisplayName); notation != c.expected {
+			t.Errorf("%s - Expected: %s, Actual: %s", c.testName, c.expected, notation)
+		}
+	}
+}
+
+// Reducing the redex an abstraction introduces gives back the original term
+func TestAbstractReverses(t *testing.T) {
+	sources := []string{"\\x. f (g y) x", "\\x. \\y. f (g x) y", "\\x. g x (\\z. g x)", "\\a. \\b. b (\\c. a b) (a b)"}
+	for _, source := range sources {
+		e := test_helpers.ParseExpr(t, source)
+		for h := range walk.ToSeq(walk.Pre(e)) {
+			for _, rewrite := range []Rewrite{Extract("v"), Abstract("v")} {
+				abstracted, path, err := rewrite(walk.NavTo(e, h.Path()).Focus())
+				if err != nil {
+					t.Fatal(err)
+				}
+				redex := beta_reduce.AsBetaRedex(walk.NavTo(abstracted, path).Focus().Expr)
+				if redex == nil {
+					t.Fatalf("%s at %s - No redex at %s", source, h.Path(), path)
+				}
+				redex.Hole = walk.NavTo(abstracted, path).Focus().Hole
+				if reduced := redex.Reduce(); !ln.AlphaEqual(reduced, e) {
+					t.Errorf("%s at %s - Reduced to %s", source, h.Path(), ln.ToLambdaNotation(reduced, ln.DisplayBoth))
+				}
+			}
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	e := test_helpers.ParseExpr(t, "\\x. f x")
+	if _, _, err := Rename("y")(walk.NavTo(e, ln.Path{0}).Focus()); err == nil {
+		t.Error("Renamed an application")
+	}
+	if _, _, err := Inline()(walk.NavTo(e, ln.Path{0, 1}).Focus()); err == nil {
+		t.Error("Inlined a variable bound by a plain lambda")
+	}
+}
@@ -0,0 +1,70 @@
+// Non-interactive reduction: every step is printed with its redex highlighted,
+// deterministically, so that traces can be scripted and compared
+package stepper
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
+	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/intern"
+	"github.com/gusbicalho/go-lambda/pretty"
+)
+
+// Brackets each line of the redex, for output without colors
+var Marked = pretty.Markers(map[pretty.Annotation][2]string{pretty.Redex: {"⟦", "⟧"}})
+
+type Options struct {
+	Strategy beta_reduce.Strategy
+	// Zero to reduce until the normal form, which may never come
+	MaxSteps uint
+	// Marked when nil
+	Renderer pretty.Renderer
+}
+
+// Stops early when a term comes back, up to binder names, since the reduction loops
+func Write(w io.Writer, e ln.Expr, options Options) error {
+	if options.Renderer == nil {
+		options.Renderer = Marked
+	}
+	out := bufio.NewWriter(w)
+	fmt.Fprintln(out, ln.ToLambdaNotation(e, ln.DisplayName))
+	var steps uint
+	terms := intern.NewTable()
+	seen := map[*intern.Node]uint{terms.Intern(e): 0}
+	for redex := options.Strategy.Next(e); redex != nil; redex = options.Strategy.Next(e) {
+		if options.MaxSteps > 0 && steps == options.MaxSteps {
+			fmt.Fprintf(out, "\nstopped after %s\n", stepCount(steps))
+			return out.Flush()
+		}
+		steps++
+		fmt.Fprintf(out, "\nstep %d, at %s:\n", steps, redex.Hole.Path())
+		err := redex.ToPrettyDoc(nil).Render(out, pretty.RenderOptions{Renderer: options.Renderer})
+		if err != nil {
+			return err
+		}
+		e = redex.Reduce()
+		fmt.Fprintln(out, "\n→β", ln.ToLambdaNotation(e, ln.DisplayName))
+		node := terms.Intern(e)
+		if before, found := seen[node]; found {
+			if before == 0 {
+				fmt.Fprintf(out, "\nloops: step %d gives back the starting term\n", steps)
+			} else {
+				fmt.Fprintf(out, "\nloops: step %d gives back the term of step %d\n", steps, before)
+			}
+			return out.Flush()
+		}
+		seen[node] = steps
+	}
+	fmt.Fprintf(out, "\nnormal form after %s\n", stepCount(steps))
+	return out.Flush()
+}
+
+func stepCount(steps uint) string {
+	if steps == 1 {
+		return "1 step"
+	}
+	return fmt.Sprint(steps, " steps")
+}
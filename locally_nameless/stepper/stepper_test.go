@@ -0,0 +1,33 @@
+package stepper
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
+	"github.com/gusbicalho/go-lambda/pretty"
+	"github.com/gusbicalho/go-lambda/test_helpers"
+)
+
+func TestTraceGolden(t *testing.T) {
+	cases := []struct {
+		golden  string
+		source  string
+		options Options
+	}{
+		{"normal", "(\\x. x x) ((\\y. y) z)", Options{Strategy: beta_reduce.NormalOrder}},
+		{"applicative", "(\\x. x x) ((\\y. y) z)", Options{Strategy: beta_reduce.ApplicativeOrder}},
+		{"omega_loops", "(\\x. x x) (\\x. x x)", Options{}},
+		{"loops_later", "(\\z. z) ((\\x. x x) (\\x. x x))", Options{}},
+		{"one_step", "(\\x. x) a", Options{}},
+		{"growing_limited", "(\\x. x x x) (\\x. x x x)", Options{MaxSteps: 2}},
+		{"church_ansi", "(\\n. \\f. \\x. f (n f x)) (\\f. \\x. f x)", Options{Renderer: pretty.ANSI(pretty.DefaultTheme)}},
+	}
+	for _, c := range cases {
+		actual := bytes.Buffer{}
+		if err := Write(&actual, test_helpers.ParseExpr(t, c.source), c.options); err != nil {
+			t.Fatal(err)
+		}
+		test_helpers.Golden(t, c.golden, actual.String())
+	}
+}
@@ -0,0 +1,73 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/metrics"
+)
+
+type ScopeError struct {
+	Path    expr.Path
+	Index   uint
+	Binders uint
+}
+
+func (err ScopeError) Error() string {
+	return fmt.Sprint(
+		"bound variable ", err.Index, " at ", err.Path,
+		" is out of scope (", err.Binders, " binders)",
+	)
+}
+
+// Reports every BoundVar whose index escapes its enclosing binders.
+// A well-scoped term yields no errors.
+func Validate(e expr.Expr) []ScopeError {
+	var errs []ScopeError
+	expr.CaseExpr(e, visitValidate{path: expr.Path{}, errs: &errs})
+	return errs
+}
+
+type NotClosedError struct {
+	FreeVars []string
+}
+
+func (err NotClosedError) Error() string {
+	return fmt.Sprint("term is not closed, free variables: ", strings.Join(err.FreeVars, ", "))
+}
+
+func CheckClosed(e expr.Expr) error {
+	if freeVars := metrics.Of(e).FreeVars; len(freeVars) > 0 {
+		return NotClosedError{FreeVars: freeVars}
+	}
+	return nil
+}
+
+type visitValidate struct {
+	path    expr.Path
+	binders uint
+	errs    *[]ScopeError
+}
+
+func (v visitValidate) child(child uint, binders uint) visitValidate {
+	return visitValidate{path: v.path.Child(child), binders: binders, errs: v.errs}
+}
+
+func (v visitValidate) CaseFree(_ expr.FreeVar) struct{} { return struct{}{} }
+
+func (v visitValidate) CaseBound(e expr.BoundVar) struct{} {
+	if e.Index() >= v.binders {
+		*v.errs = append(*v.errs, ScopeError{Path: v.path, Index: e.Index(), Binders: v.binders})
+	}
+	return struct{}{}
+}
+
+func (v visitValidate) CaseLambda(e expr.Lambda) struct{} {
+	return expr.CaseExpr(e.Body(), v.child(0, v.binders+1))
+}
+
+func (v visitValidate) CaseApp(e expr.App) struct{} {
+	expr.CaseExpr(e.Callee(), v.child(0, v.binders))
+	return expr.CaseExpr(e.Arg(), v.child(1, v.binders))
+}
@@ -0,0 +1,62 @@
+package validate
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		testName string
+		e        expr.Expr
+		expected []ScopeError
+	}{
+		{"free", expr.NewFree("a"), nil},
+		{"well scoped", expr.NewLambda("x", expr.NewLambda("y", expr.NewApp(expr.NewBound(1), expr.NewBound(0)))), nil},
+		{"bound at the root", expr.NewBound(0), []ScopeError{{Path: expr.Path{}, Index: 0, Binders: 0}}},
+		{
+			"one past the binders",
+			// \x. \y. y 2
+			expr.NewLambda("x", expr.NewLambda("y", expr.NewApp(expr.NewBound(0), expr.NewBound(2)))),
+			[]ScopeError{{Path: expr.Path{0, 0, 1}, Index: 2, Binders: 2}},
+		},
+		{
+			"every error, in order",
+			// (\x. 1) (\x. 0 3) 0
+			expr.NewApp(
+				expr.NewApp(expr.NewLambda("x", expr.NewBound(1)), expr.NewLambda("x", expr.NewApp(expr.NewBound(0), expr.NewBound(3)))),
+				expr.NewBound(0),
+			),
+			[]ScopeError{
+				{Path: expr.Path{0, 0, 0}, Index: 1, Binders: 1},
+				{Path: expr.Path{0, 1, 0, 1}, Index: 3, Binders: 1},
+				{Path: expr.Path{1}, Index: 0, Binders: 0},
+			},
+		},
+	}
+	for _, c := range cases {
+		actual := Validate(c.e)
+		if !slices.EqualFunc(actual, c.expected, func(a, b ScopeError) bool {
+			return slices.Equal(a.Path, b.Path) && a.Index == b.Index && a.Binders == b.Binders
+		}) {
+			t.Errorf("%s - Expected %v, got %v", c.testName, c.expected, actual)
+		}
+	}
+}
+
+func TestCheckClosed(t *testing.T) {
+	if err := CheckClosed(expr.NewLambda("x", expr.NewBound(0))); err != nil {
+		t.Errorf("Expected a closed term, got %s", err)
+	}
+	// \x. b x a
+	open := expr.NewLambda("x", expr.NewApp(expr.NewApp(expr.NewFree("b"), expr.NewBound(0)), expr.NewFree("a")))
+	err, ok := CheckClosed(open).(NotClosedError)
+	if !ok {
+		t.Fatalf("Expected a NotClosedError, got %v", CheckClosed(open))
+	}
+	if !slices.Equal(err.FreeVars, []string{"a", "b"}) {
+		t.Errorf("Expected free variables a and b, got %v", err.FreeVars)
+	}
+}
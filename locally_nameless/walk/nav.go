@@ -25,6 +25,19 @@ func ToNav(e expr.Expr) Nav {
 	}
 }
 
+// Nil when there is no subterm at the path
+func NavTo(e expr.Expr, path expr.Path) *Nav {
+	nav := ToNav(e)
+	for _, child := range path {
+		next := nav.Child(child)
+		if next == nil {
+			return nil
+		}
+		nav = *next
+	}
+	return &nav
+}
+
 func (nav Nav) Parent() (*Nav, uint) {
 	if nav.parent == nil {
 		return nil, 0
@@ -36,6 +49,15 @@ func (nav Nav) Parent() (*Nav, uint) {
 	return &Nav{parent: parent, expr: hole.Fill(nav.expr)}, index
 }
 
+func (nav Nav) Path() expr.Path {
+	path := expr.Path{}
+	for p := nav.parent; p != nil; p = p.parent {
+		path = append(path, p.index)
+	}
+	slices.Reverse(path)
+	return path
+}
+
 func (nav Nav) UpdateExpr(update func(expr.Expr) *expr.Expr) (Nav, bool) {
 	newExpr := update(nav.expr)
 	if newExpr != nil {
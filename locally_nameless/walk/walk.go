@@ -2,6 +2,7 @@ package walk
 
 import (
 	"iter"
+	"slices"
 
 	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
 	"github.com/gusbicalho/go-lambda/locally_nameless/hole"
@@ -39,7 +40,17 @@ func ToSeq(nav Walk) iter.Seq2[hole.Hole, expr.Expr] {
 func (focus Focus) ToPrettyDoc() pretty.Doc {
 	return focus.Hole.ToPrettyDoc(
 		func(ctx expr.DisplayContext) pretty.Doc {
-			return pretty.TViewInvert(lnpretty.ExprToPrettyDoc(focus.Expr, ctx))
+			return pretty.Annotate(pretty.Highlight, lnpretty.ExprToPrettyDoc(focus.Expr, ctx))
+		},
+	)
+}
+
+// The focus is rendered with fresh limits, so it is never elided
+func (focus Focus) ToPrettyDocWith(options lnpretty.Options, focusPath expr.Path) pretty.Doc {
+	options.Expanded = append(slices.Clip(options.Expanded), focusPath)
+	return focus.Hole.ToPrettyDocWith(options,
+		func(pos lnpretty.Position) pretty.Doc {
+			return pretty.Annotate(pretty.Highlight, lnpretty.ExprAtToPrettyDoc(focus.Expr, pos))
 		},
 	)
 }
@@ -0,0 +1,24 @@
+package walk
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/generate"
+)
+
+func TestPostFillRebuilds(t *testing.T) {
+	rebuilds := func(e expr.Expr) bool {
+		original := expr.ToLambdaNotation(e, expr.DisplayBoth)
+		for h, subterm := range Post(e) {
+			if expr.ToLambdaNotation(h.Fill(subterm), expr.DisplayBoth) != original {
+				return false
+			}
+		}
+		return true
+	}
+	if e, ok := generate.Check(rand.New(rand.NewPCG(1, 2)), 500, 30, rebuilds); !ok {
+		t.Errorf("A hole filled with its subterm does not rebuild %s", expr.ToLambdaNotation(e, expr.DisplayBoth))
+	}
+}
@@ -0,0 +1,87 @@
+package locally_nameless_to_parse_tree
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/metrics"
+	"github.com/gusbicalho/go-lambda/parse_tree"
+)
+
+// Binders are renamed as needed so that they never capture
+// free variables or outer binders, so that parsing the result
+// with ToLocallyNameless yields back the original expr
+func FromLocallyNameless(e expr.Expr) parse_tree.ParseTree {
+	ctx := expr.EmptyContext().WithReserved(metrics.Of(e).FreeVars...)
+	return fromLocallyNameless(e, ctx)
+}
+
+func fromLocallyNameless(e expr.Expr, ctx expr.DisplayContext) parse_tree.ParseTree {
+	return parse_tree.ParseTree{Item: expr.CaseExpr(e, visitFrom{ctx})}
+}
+
+type visitFrom struct{ ctx expr.DisplayContext }
+
+func (v visitFrom) CaseFree(e expr.FreeVar) parse_tree.ParseItem {
+	return parse_tree.Var{Name: e.Name()}
+}
+
+func (v visitFrom) CaseBound(e expr.BoundVar) parse_tree.ParseItem {
+	name, found := v.ctx.BoundName(e.Index())
+	if !found {
+		panic(fmt.Sprint("Out of scope bound variable ", e.Index()))
+	}
+	return parse_tree.Var{Name: name}
+}
+
+func (v visitFrom) CaseLambda(e expr.Lambda) parse_tree.ParseItem {
+	ctx, argName := v.ctx.BindFree(e.ArgName())
+	return parse_tree.Lambda{
+		ArgName: argName,
+		Body:    fromLocallyNameless(e.Body(), ctx),
+	}
+}
+
+func (v visitFrom) CaseApp(e expr.App) parse_tree.ParseItem {
+	args := []parse_tree.ParseTree{}
+	var callee expr.Expr = e
+	for {
+		app, ok := callee.(expr.App)
+		if !ok {
+			break
+		}
+		args = append(args, v.parenthesized(app.Arg(), isAppOrLambda))
+		callee = app.Callee()
+	}
+	slices.Reverse(args)
+	return parse_tree.App{
+		Callee: v.parenthesized(callee, isLambda),
+		Args: parse_tree.AppArgs{
+			First: args[0],
+			More:  args[1:],
+		},
+	}
+}
+
+func (v visitFrom) parenthesized(e expr.Expr, needsParens func(expr.Expr) bool) parse_tree.ParseTree {
+	tree := fromLocallyNameless(e, v.ctx)
+	if needsParens(e) {
+		return parse_tree.ParseTree{Item: parse_tree.Parens{Child: tree}}
+	}
+	return tree
+}
+
+func isLambda(e expr.Expr) bool {
+	_, ok := e.(expr.Lambda)
+	return ok
+}
+
+func isAppOrLambda(e expr.Expr) bool {
+	switch e.(type) {
+	case expr.App, expr.Lambda:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,63 @@
+package locally_nameless_to_parse_tree
+
+import (
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/format"
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/generate"
+	"github.com/gusbicalho/go-lambda/parse_tree_to_locally_nameless"
+	"github.com/gusbicalho/go-lambda/parser"
+	"github.com/gusbicalho/go-lambda/tokenizer"
+)
+
+// Prints the term, and parses it back
+func roundTrip(e expr.Expr) (string, expr.Expr, error) {
+	printed := strings.TrimSuffix(format.FormatTree(FromLocallyNameless(e), math.MaxUint), "\n")
+	parsed, err := parser.Parse(tokenizer.New(strings.NewReader(printed)))
+	if err != nil {
+		return printed, nil, err
+	}
+	return printed, parse_tree_to_locally_nameless.ToLocallyNameless(*parsed), nil
+}
+
+func TestFromLocallyNameless(t *testing.T) {
+	cases := []struct {
+		testName string
+		e        expr.Expr
+		expected string
+	}{
+		{"names kept", expr.NewLambda("f", expr.NewLambda("x", expr.NewApp(expr.NewBound(1), expr.NewBound(0)))), "\\f. \\x. f x"},
+		{"shadowing binder renamed", expr.NewLambda("x", expr.NewLambda("x", expr.NewBound(1))), "\\x. \\x_0. x"},
+		{"binder renamed past a free variable", expr.NewApp(expr.NewLambda("x", expr.NewApp(expr.NewBound(0), expr.NewFree("x"))), expr.NewFree("x")), "(\\x_0. x_0 x) x"},
+		{"renamed past a taken name", expr.NewLambda("x", expr.NewLambda("x", expr.NewApp(expr.NewBound(0), expr.NewFree("x_0")))), "\\x. \\x_1. x_1 x_0"},
+		{"parens", expr.NewApp(expr.NewApp(expr.NewFree("f"), expr.NewApp(expr.NewFree("g"), expr.NewFree("a"))), expr.NewLambda("y", expr.NewBound(0))), "f (g a) \\y. y"},
+	}
+	for _, c := range cases {
+		printed, reparsed, err := roundTrip(c.e)
+		if err != nil {
+			t.Errorf("%s - %s: %s", c.testName, printed, err)
+			continue
+		}
+		if printed != c.expected {
+			t.Errorf("%s - Expected: %s, Actual: %s", c.testName, c.expected, printed)
+		}
+		if actual, expected := expr.ToLambdaNotation(reparsed, expr.DisplayDeBruijn), expr.ToLambdaNotation(c.e, expr.DisplayDeBruijn); actual != expected {
+			t.Errorf("%s - Parsed back as %s, expected %s", c.testName, actual, expected)
+		}
+	}
+}
+
+// Printing and parsing again gives back the same term, up to binder names
+func TestRoundTripProperty(t *testing.T) {
+	roundTrips := func(e expr.Expr) bool {
+		_, reparsed, err := roundTrip(e)
+		return err == nil && expr.ToLambdaNotation(reparsed, expr.DisplayDeBruijn) == expr.ToLambdaNotation(e, expr.DisplayDeBruijn)
+	}
+	if e, ok := generate.Check(rand.New(rand.NewPCG(1, 2)), 500, 30, roundTrips); !ok {
+		t.Errorf("%s does not round-trip", expr.ToLambdaNotation(e, expr.DisplayBoth))
+	}
+}
@@ -3,23 +3,39 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"math"
 	"os"
 	"slices"
-	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	ln_beta_reduce "github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
 	ln_expr "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/metrics"
 	ln_pretty "github.com/gusbicalho/go-lambda/locally_nameless/pretty"
 	"github.com/gusbicalho/go-lambda/locally_nameless/walk"
-	"github.com/gusbicalho/go-lambda/parse_tree_to_locally_nameless"
-	"github.com/gusbicalho/go-lambda/parser"
-	"github.com/gusbicalho/go-lambda/tokenizer"
+	"github.com/gusbicalho/go-lambda/pretty"
+	"github.com/gusbicalho/go-lambda/session"
+	"github.com/gusbicalho/go-lambda/tui"
 
 	"github.com/rivo/tview"
 )
 
+var (
+	tviewRenderer = pretty.TView(pretty.DefaultTheme)
+	ansiRenderer  = pretty.ANSI(pretty.DefaultTheme)
+)
+
 func main() {
+	if len(os.Args) > 1 {
+		if command, found := commands[os.Args[1]]; found {
+			if err := command(os.Args[2:]); err != nil {
+				fmt.Fprintln(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	var source string
 	if len(os.Args) > 1 {
 		source = os.Args[1]
@@ -31,21 +47,27 @@ func main() {
 		source = line
 	}
 
-	parseTree, err := parser.Parse(tokenizer.New(strings.NewReader(source)))
+	saved, err := session.New(source)
 	if err != nil {
 		fmt.Println(err.Error())
 		return
 	}
 
-	expr := parse_tree_to_locally_nameless.ToLocallyNameless(*parseTree)
+	keys, err := loadKeymap("default", "")
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
 
-	//tui(expr)
+	//tui1(expr)
 	// tui2(expr)
-	tui3(expr)
+	if err := runTui(saved, tui.Options{Keys: keys, Speed: 2}); err != nil {
+		panic(err)
+	}
 	//run(expr)
 }
 
-func tui(expr ln_expr.Expr) {
+func tui1(expr ln_expr.Expr) {
 	app := tview.NewApplication()
 	textView := tview.NewTextView().
 		SetDynamicColors(true).
@@ -101,9 +123,9 @@ func tui(expr ln_expr.Expr) {
 	redraw := func() {
 		var pretty string
 		if redex := getSelectedRedex(); redex != nil {
-			pretty = redex.ToPrettyDoc(nil).String()
+			pretty = redex.ToPrettyDoc(nil).PrettyWith(tviewRenderer, math.MaxUint)
 		} else {
-			pretty = ln_pretty.ToPrettyDoc(expr).String() + "\nIrreducible."
+			pretty = ln_pretty.ToPrettyDoc(expr).PrettyWith(tviewRenderer, math.MaxUint) + "\nIrreducible."
 		}
 
 		textView.Clear()
@@ -186,7 +208,7 @@ func tui2(expr ln_expr.Expr) {
 	}
 
 	redraw := func() {
-		var pretty = walk.ToPrettyDoc(walking).String()
+		var pretty = walk.ToPrettyDoc(walking).PrettyWith(tviewRenderer, math.MaxUint)
 
 		textView.Clear()
 		fmt.Fprintf(
@@ -224,173 +246,19 @@ func tui2(expr ln_expr.Expr) {
 	}
 }
 
-func tui3(expr ln_expr.Expr) {
-	app := tview.NewApplication()
-	textView := newExprView(app)
-
-	log := []string{ln_expr.ToLambdaNotation(expr, ln_expr.DisplayName)}
-	nav := walk.ToNav(expr)
-
-	stop := func() {
-		app.Stop()
-		for _, logEntry := range log {
-			fmt.Println(logEntry)
-		}
-	}
-
-	step := func() {
-		var updated bool
-		nav, updated = nav.UpdateExpr(func(e ln_expr.Expr) *ln_expr.Expr {
-			redex := ln_beta_reduce.AsBetaRedex(e)
-			if redex == nil {
-				return nil
-			}
-			e = redex.Reduce()
-			return &e
-		})
-		if updated {
-			expr = nav.Focus().Realize()
-			log = append(log, ln_expr.ToLambdaNotation(expr, ln_expr.DisplayName))
-		}
-	}
-
-	left := func() {
-		if parent, _ := nav.Parent(); parent != nil {
-			nav = *parent
-		}
-	}
-
-	right := func() {
-		if child := nav.Child(0); child != nil {
-			nav = *child
-		}
-	}
-
-	down := func() {
-		n := &nav
-		for {
-			parent, index := n.Parent()
-			if parent == nil {
-				break
-			}
-			if sibling := parent.Child(index + 1); sibling != nil {
-				nav = *sibling
-				break
-			}
-			n = parent
-		}
-	}
-
-	up := func() {
-		parent, index := nav.Parent()
-		if parent == nil {
-			return
-		}
-		if index > 0 {
-			if sibling := parent.Child(index - 1); sibling != nil {
-				nav = *sibling
-				if child := nav.Child(nav.Children() - 1); child != nil {
-					nav = *child
-				}
-				return
-			}
-		}
-		nav = *parent
-	}
-
-	redraw := func() {
-		var pretty = nav.Focus().ToPrettyDoc().String()
-
-		textView.Clear()
-		fmt.Fprintf(
-			textView, "%s\n\n%s\n\n%s\n%s",
-			ln_expr.ToLambdaNotation(expr, ln_expr.DisplayName),
-			pretty,
-			fmt.Sprint(expr),
-			fmt.Sprint(nav),
-		)
-	}
-	go redraw()
-
-	textView.SetKeyHandler(func(key tcell.Key) bool {
-		switch key {
-		case tcell.KeyESC:
-			stop()
-		case tcell.KeyEnter:
-			step()
-			redraw()
-		case tcell.KeyLeft:
-			left()
-			redraw()
-		case tcell.KeyRight:
-			right()
-			redraw()
-		case tcell.KeyUp:
-			up()
-			redraw()
-		case tcell.KeyDown:
-			down()
-			redraw()
-		default:
-			return false
-		}
-		return true
-	})
-
-	textView.SetBorder(true)
-	if err := app.SetRoot(textView, true).SetFocus(textView).Run(); err != nil {
-		panic(err)
-	}
-}
-
-type exprView struct {
-	*tview.TextView
-	onKey func(tcell.Key) bool
-}
-
-func newExprView(app *tview.Application) *exprView {
-	return &exprView{
-		tview.NewTextView().
-			SetDynamicColors(true).
-			SetRegions(true).
-			SetChangedFunc(
-				func() {
-					app.Draw()
-				},
-			),
-		nil,
-	}
-}
-
-func (t *exprView) SetKeyHandler(onKey func(key tcell.Key) bool) {
-	t.onKey = onKey
-}
-
-func (t *exprView) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
-	super := t.TextView.InputHandler()
-	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
-		if t.onKey != nil && event != nil {
-			key := event.Key()
-			if t.onKey(key) {
-				return
-			}
-		}
-		super(event, setFocus)
-	})
-}
-
 func run(expr ln_expr.Expr) {
 	reader := bufio.NewReader(os.Stdin)
 
 	for {
 		fmt.Println(ln_expr.ToLambdaNotation(expr, ln_expr.DisplayName))
-		redex := nextBetaRedex(expr)
+		fmt.Println(metrics.Of(expr))
+		redex := ln_beta_reduce.ApplicativeOrder.Next(expr)
 		if redex == nil {
-			fmt.Println(ln_pretty.ToPrettyDoc(expr).String())
+			printDoc(ln_pretty.ToPrettyDoc(expr))
 			fmt.Println("Irreducible.")
 			break
 		}
-		fmt.Println(redex.ToPrettyDoc(nil).String())
+		printDoc(redex.ToPrettyDoc(nil))
 		fmt.Print("Step? ")
 		_, err := reader.ReadString('\n')
 		if err != nil {
@@ -401,9 +269,9 @@ func run(expr ln_expr.Expr) {
 	}
 }
 
-func nextBetaRedex(expr ln_expr.Expr) *ln_beta_reduce.BetaRedex {
-	for redex := range ln_beta_reduce.BetaRedexes(expr) {
-		return &redex
+func printDoc(doc pretty.Doc) {
+	if err := doc.Render(os.Stdout, pretty.RenderOptions{Renderer: ansiRenderer}); err != nil {
+		panic(err)
 	}
-	return nil
+	fmt.Println()
 }
@@ -0,0 +1,176 @@
+// Beta reduction on named terms, by the textbook capture-avoiding substitution.
+// It is a second engine to compare with the locally nameless one: binders keep
+// their names, and are renamed to fresh ones only when they would capture.
+package named_beta_reduce
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gusbicalho/go-lambda/parse_tree"
+	"github.com/gusbicalho/go-lambda/stack"
+)
+
+func BetaReduce(lambda parse_tree.Lambda, arg parse_tree.ParseTree) parse_tree.ParseTree {
+	return Subst(lambda.Body, lambda.ArgName, arg)
+}
+
+// [name := arg] body
+func Subst(body parse_tree.ParseTree, name string, arg parse_tree.ParseTree) parse_tree.ParseTree {
+	return subst(body, name, arg, FreeVars(arg))
+}
+
+func subst(body parse_tree.ParseTree, name string, arg parse_tree.ParseTree, argFree []string) parse_tree.ParseTree {
+	switch item := body.Item.(type) {
+	case parse_tree.Parens:
+		body.Item = parse_tree.Parens{Child: subst(item.Child, name, arg, argFree)}
+	case parse_tree.Var:
+		if item.Name == name {
+			return parenthesized(arg)
+		}
+	case parse_tree.Lambda:
+		argName, lambdaBody := substUnder(item.ArgName, item.Body, name, arg, argFree)
+		body.Item = parse_tree.Lambda{ArgName: argName, Body: lambdaBody}
+	case parse_tree.Let:
+		letName, letBody := substUnder(item.Name, item.Body, name, arg, argFree)
+		body.Item = parse_tree.Let{Name: letName, Value: subst(item.Value, name, arg, argFree), Body: letBody}
+	case parse_tree.App:
+		more := make([]parse_tree.ParseTree, len(item.Args.More))
+		for i, moreArg := range item.Args.More {
+			more[i] = subst(moreArg, name, arg, argFree)
+		}
+		body.Item = parse_tree.App{
+			Callee: subst(item.Callee, name, arg, argFree),
+			Args:   parse_tree.AppArgs{First: subst(item.Args.First, name, arg, argFree), More: more},
+		}
+	default:
+		panic("unknown parse tree")
+	}
+	return body
+}
+
+// Substitutes in the scope of a binder, renaming the binder if it would capture
+// a free variable of the argument
+func substUnder(
+	binder string, scope parse_tree.ParseTree, name string, arg parse_tree.ParseTree, argFree []string,
+) (string, parse_tree.ParseTree) {
+	if binder == name {
+		// The name is shadowed, so nothing in scope refers to the one substituted
+		return binder, scope
+	}
+	if !slices.Contains(argFree, binder) {
+		return binder, subst(scope, name, arg, argFree)
+	}
+	scopeFree := FreeVars(scope)
+	if !slices.Contains(scopeFree, name) {
+		return binder, scope
+	}
+	renamed := Fresh(binder, func(candidate string) bool {
+		return candidate == name || slices.Contains(argFree, candidate) || slices.Contains(scopeFree, candidate)
+	})
+	renamedVar := parse_tree.ParseTree{Item: parse_tree.Var{Name: renamed}}
+	return renamed, subst(Subst(scope, binder, renamedVar), name, arg, argFree)
+}
+
+// The first of name_0, name_1, ... that is not used
+func Fresh(name string, used func(name string) bool) string {
+	for i := 0; ; i++ {
+		candidate := fmt.Sprint(name, "_", i)
+		if !used(candidate) {
+			return candidate
+		}
+	}
+}
+
+// Sorted, without duplicates
+func FreeVars(tree parse_tree.ParseTree) []string {
+	free := []string{}
+	collectFreeVars(tree, stack.Empty[string](), &free)
+	slices.Sort(free)
+	return slices.Compact(free)
+}
+
+func collectFreeVars(tree parse_tree.ParseTree, bound stack.Stack[string], free *[]string) {
+	switch item := tree.Item.(type) {
+	case parse_tree.Parens:
+		collectFreeVars(item.Child, bound, free)
+	case parse_tree.Var:
+		for boundName := range bound.Items() {
+			if boundName == item.Name {
+				return
+			}
+		}
+		*free = append(*free, item.Name)
+	case parse_tree.Lambda:
+		collectFreeVars(item.Body, bound.Push(item.ArgName), free)
+	case parse_tree.Let:
+		collectFreeVars(item.Value, bound, free)
+		collectFreeVars(item.Body, bound.Push(item.Name), free)
+	case parse_tree.App:
+		collectFreeVars(item.Callee, bound, free)
+		collectFreeVars(item.Args.First, bound, free)
+		for _, arg := range item.Args.More {
+			collectFreeVars(arg, bound, free)
+		}
+	}
+}
+
+// Contracts the leftmost outermost redex, like the locally nameless NormalOrder.
+// A let is a redex, being sugar for (\x. b) v. Returns false when in normal form.
+func Step(tree parse_tree.ParseTree) (parse_tree.ParseTree, bool) {
+	switch item := tree.Item.(type) {
+	case parse_tree.Parens:
+		child, stepped := Step(item.Child)
+		tree.Item = parse_tree.Parens{Child: child}
+		return tree, stepped
+	case parse_tree.Lambda:
+		body, stepped := Step(item.Body)
+		tree.Item = parse_tree.Lambda{ArgName: item.ArgName, Body: body}
+		return tree, stepped
+	case parse_tree.Let:
+		return Subst(item.Body, item.Name, item.Value), true
+	case parse_tree.App:
+		if lambda, ok := unparenthesized(item.Callee).Item.(parse_tree.Lambda); ok {
+			reduct := BetaReduce(lambda, item.Args.First)
+			if len(item.Args.More) == 0 {
+				return reduct, true
+			}
+			tree.Item = parse_tree.App{
+				Callee: parenthesized(reduct),
+				Args:   parse_tree.AppArgs{First: item.Args.More[0], More: item.Args.More[1:]},
+			}
+			return tree, true
+		}
+		// The callee, then each argument, as they nest to the left
+		callee, stepped := Step(item.Callee)
+		args := append([]parse_tree.ParseTree{item.Args.First}, item.Args.More...)
+		for i := 0; i < len(args) && !stepped; i++ {
+			args[i], stepped = Step(args[i])
+		}
+		if !stepped {
+			return tree, false
+		}
+		tree.Item = parse_tree.App{Callee: callee, Args: parse_tree.AppArgs{First: args[0], More: args[1:]}}
+		return tree, true
+	}
+	return tree, false
+}
+
+// Only variables and parenthesized terms can go anywhere a variable can
+func parenthesized(tree parse_tree.ParseTree) parse_tree.ParseTree {
+	switch tree.Item.(type) {
+	case parse_tree.Var, parse_tree.Parens:
+		return tree
+	}
+	return parse_tree.ParseTree{Item: parse_tree.Parens{Child: tree}}
+}
+
+func unparenthesized(tree parse_tree.ParseTree) parse_tree.ParseTree {
+	for {
+		parens, ok := tree.Item.(parse_tree.Parens)
+		if !ok {
+			return tree
+		}
+		tree = parens.Child
+	}
+}
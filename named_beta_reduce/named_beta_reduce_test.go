@@ -0,0 +1,85 @@
+package named_beta_reduce
+
+import (
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/format"
+	"github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
+	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/generate"
+	"github.com/gusbicalho/go-lambda/parse_tree"
+	"github.com/gusbicalho/go-lambda/parse_tree_to_locally_nameless"
+	"github.com/gusbicalho/go-lambda/test_helpers"
+)
+
+func notation(tree parse_tree.ParseTree) string {
+	return strings.TrimSuffix(format.FormatTree(tree, math.MaxUint), "\n")
+}
+
+func TestStep(t *testing.T) {
+	cases := []struct {
+		testName string
+		source   string
+		expected string
+	}{
+		{"identity", "(\\x. x) a", "a"},
+		{"shadowed", "(\\x. \\x. x) a", "\\x. x"},
+		{"capture", "(\\x. \\y. x y) y", "\\y_0. y y_0"},
+		{"fresh name taken", "(\\x. \\y. x y y_0) y", "\\y_1. y y_1 y_0"},
+		{"no capture without occurrence", "(\\x. \\y. y) y", "\\y. y"},
+		{"argument needs parens", "(\\x. x b) (f a)", "f a b"},
+		{"lambda as callee", "(\\x. x b) (\\y. y)", "(\\y. y) b"},
+		{"more arguments", "(\\x. x) f a b", "f a b"},
+		{"inside an argument", "f ((\\x. x) a) b", "f a b"},
+		{"let", "let y = z in \\z. y z", "\\z_0. z z_0"},
+	}
+	for _, c := range cases {
+		actual, stepped := Step(test_helpers.Parse(t, c.source))
+		if !stepped {
+			t.Errorf("%s - Expected a step", c.testName)
+			continue
+		}
+		if notation := notation(actual); notation != c.expected {
+			t.Errorf("%s - Expected: %s, Actual: %s", c.testName, c.expected, notation)
+		}
+	}
+	if _, stepped := Step(test_helpers.Parse(t, "\\x. f x (g y)")); stepped {
+		t.Error("Stepped a normal form")
+	}
+}
+
+// Both engines take the same normal order steps, to alpha-equivalent terms
+func TestAgreesWithLocallyNameless(t *testing.T) {
+	agrees := func(e ln.Expr) bool {
+		// Printed with the binder names the generator picked, some of which
+		// capture free variables: those terms are not what they print as
+		tree := test_helpers.Parse(t, ln.ToLambdaNotation(e, ln.DisplayName))
+		if !ln.AlphaEqual(parse_tree_to_locally_nameless.ToLocallyNameless(tree), e) {
+			return true
+		}
+		for range 20 {
+			redex := beta_reduce.NormalOrder.Next(e)
+			next, stepped := Step(tree)
+			if stepped != (redex != nil) {
+				return false
+			}
+			if !stepped {
+				return true
+			}
+			e = redex.Reduce()
+			// Printed and parsed again, in case the parens went wrong
+			reparsed := test_helpers.Parse(t, notation(next))
+			if !ln.AlphaEqual(parse_tree_to_locally_nameless.ToLocallyNameless(reparsed), e) {
+				return false
+			}
+			tree = next
+		}
+		return true
+	}
+	if e, ok := generate.Check(rand.New(rand.NewPCG(1, 2)), 2000, 20, agrees); !ok {
+		t.Errorf("The engines disagree, reducing %s", ln.ToLambdaNotation(e, ln.DisplayName))
+	}
+}
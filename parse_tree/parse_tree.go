@@ -9,7 +9,11 @@ import (
 
 type ParseTree struct {
 	InputLocation position.Position
-	Item          ParseItem
+	// Line comments preceding the tree
+	Comments []string
+	// Line comments following the tree, only found at the end of the input
+	TrailingComments []string
+	Item             ParseItem
 }
 
 func (t ParseTree) ToPrettyDoc(ctx any) pretty.Doc {
@@ -79,6 +83,22 @@ func (item App) ToPrettyDoc(ctx any) pretty.Doc {
 	)
 }
 
+type Let struct {
+	Name  string
+	Value ParseTree
+	Body  ParseTree
+}
+
+func (Let) sealed() {}
+func (item Let) ToPrettyDoc(ctx any) pretty.Doc {
+	return pretty.Sequence(
+		pretty.FromString(fmt.Sprint("let ", item.Name, " =")),
+		pretty.Indent(2, item.Value.ToPrettyDoc(ctx)),
+		pretty.FromString("in"),
+		pretty.Indent(2, item.Body.ToPrettyDoc(ctx)),
+	)
+}
+
 type AppArgs struct {
 	First ParseTree
 	More  []ParseTree
@@ -10,6 +10,16 @@ func ToLocallyNameless(parsed parse_tree.ParseTree) expr.Expr {
 	return toLocallyNameless(parsed, stack.Empty[string]())
 }
 
+// Converts a term that appears under binders, e.g. to fill a hole.
+// Scope holds the names they bind, innermost first, so that scope[i] is bound variable i.
+func ToLocallyNamelessIn(parsed parse_tree.ParseTree, scope []string) expr.Expr {
+	bound := stack.Empty[string]()
+	for i := len(scope) - 1; i >= 0; i-- {
+		bound = bound.Push(scope[i])
+	}
+	return toLocallyNameless(parsed, bound)
+}
+
 func toLocallyNameless(parsed parse_tree.ParseTree, bound stack.Stack[string]) expr.Expr {
 	switch item := parsed.Item.(type) {
 	case parse_tree.Parens:
@@ -26,6 +36,12 @@ func toLocallyNameless(parsed parse_tree.ParseTree, bound stack.Stack[string]) e
 			item.ArgName,
 			toLocallyNameless(item.Body, bound.Push(item.ArgName)),
 		)
+	case parse_tree.Let:
+		// let x = v in b is sugar for (\x. b) v
+		return expr.NewApp(
+			expr.NewLambda(item.Name, toLocallyNameless(item.Body, bound.Push(item.Name))),
+			toLocallyNameless(item.Value, bound),
+		)
 	case parse_tree.App:
 		app := expr.NewApp(
 			toLocallyNameless(item.Callee, bound),
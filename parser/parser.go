@@ -25,9 +25,11 @@ func Parse(tokenizer *tokenizer.Tokenizer) (*parse_tree.ParseTree, error) {
 	if result.error != nil {
 		return nil, result.error
 	}
-	if tok := tokenizer.Next(); tok.Type() != token.EOF {
+	tok := tokenizer.Next()
+	if tok.Type() != token.EOF {
 		return nil, errors.New(fmt.Sprint("Expected EOF, found ", tok))
 	}
+	result.value.TrailingComments = tok.Comments
 	return result.value, nil
 }
 
@@ -45,10 +47,14 @@ func parseApplicable(tokenizer *tokenizer.Tokenizer) ParseResult[*parse_tree.Par
 	case token.Lambda:
 		tokenizer.Next()
 		return parseLambda(tokenizer, tok).consumedInput()
+	case token.Let:
+		tokenizer.Next()
+		return parseLet(tokenizer, tok).consumedInput()
 	case token.Identifier:
 		tokenizer.Next()
 		callee := &parse_tree.ParseTree{
 			InputLocation: tok.Position,
+			Comments:      tok.Comments,
 			Item:          parse_tree.Var{Name: tok.Value},
 		}
 		return ParseResult[*parse_tree.ParseTree]{value: callee, hasConsumedInput: true}
@@ -71,6 +77,7 @@ func parseParenTree(tokenizer *tokenizer.Tokenizer, leftParen token.Token) Parse
 		return ParseResult[*parse_tree.ParseTree]{
 			value: &parse_tree.ParseTree{
 				InputLocation: leftParen.Position,
+				Comments:      leftParen.Comments,
 				Item: parse_tree.Parens{
 					Child: *child.value,
 				},
@@ -146,9 +153,11 @@ func parseLambda(tokenizer *tokenizer.Tokenizer, lambdaTok token.Token) ParseRes
 	if bodyResult.error != nil {
 		return bodyResult
 	}
+	bodyResult.value.Comments = append(argNameTok.Comments, bodyResult.value.Comments...)
 	return ParseResult[*parse_tree.ParseTree]{
 		value: &parse_tree.ParseTree{
 			InputLocation: lambdaTok.Position,
+			Comments:      lambdaTok.Comments,
 			Item: parse_tree.Lambda{
 				ArgName: argNameTok.Value,
 				Body:    *bodyResult.value,
@@ -156,3 +165,38 @@ func parseLambda(tokenizer *tokenizer.Tokenizer, lambdaTok token.Token) ParseRes
 		},
 	}
 }
+
+func parseLet(tokenizer *tokenizer.Tokenizer, letTok token.Token) ParseResult[*parse_tree.ParseTree] {
+	nameTok := tokenizer.Next()
+	if nameTok.Type() != token.Identifier {
+		return ParseResult[*parse_tree.ParseTree]{error: errors.New(fmt.Sprint("Expected identifier, found ", nameTok))}
+	}
+	equalsTok := tokenizer.Next()
+	if equalsTok.Type() != token.Equals {
+		return ParseResult[*parse_tree.ParseTree]{error: errors.New(fmt.Sprint("Expected =, found ", equalsTok))}
+	}
+	valueResult := parseTree(tokenizer)
+	if valueResult.error != nil {
+		return valueResult
+	}
+	inTok := tokenizer.Next()
+	if inTok.Type() != token.In {
+		return ParseResult[*parse_tree.ParseTree]{error: errors.New(fmt.Sprint("Expected in, found ", inTok))}
+	}
+	bodyResult := parseTree(tokenizer)
+	if bodyResult.error != nil {
+		return bodyResult
+	}
+	valueResult.value.Comments = append(nameTok.Comments, valueResult.value.Comments...)
+	return ParseResult[*parse_tree.ParseTree]{
+		value: &parse_tree.ParseTree{
+			InputLocation: letTok.Position,
+			Comments:      letTok.Comments,
+			Item: parse_tree.Let{
+				Name:  nameTok.Value,
+				Value: *valueResult.value,
+				Body:  *bodyResult.value,
+			},
+		},
+	}
+}
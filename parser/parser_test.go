@@ -0,0 +1,29 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/tokenizer"
+)
+
+// let, in and = are reserved, so sources using let or in as variable names no longer parse
+func TestReservedWords(t *testing.T) {
+	cases := []struct {
+		source   string
+		expected string
+	}{
+		{"\\in. in", "Expected identifier, found IN at 1:1"},
+		{"\\x. in", "Unexpected token IN at 1:4"},
+		{"\\let. let", "Expected identifier, found LET at 1:1"},
+		{"let = x in x", "Expected identifier, found EQUALS at 1:4"},
+	}
+	for _, c := range cases {
+		_, err := Parse(tokenizer.New(strings.NewReader(c.source)))
+		if err == nil {
+			t.Errorf("%s: expected an error", c.source)
+		} else if err.Error() != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.source, c.expected, err.Error())
+		}
+	}
+}
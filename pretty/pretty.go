@@ -1,9 +1,12 @@
 package pretty
 
 import (
+	"bufio"
 	"io"
+	"math"
 	"strings"
-	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
 )
 
 type Pretty[context any] interface {
@@ -12,36 +15,113 @@ type Pretty[context any] interface {
 
 type Doc struct{ impl prettyDocImpl }
 
+// Renders the doc as plain text with unlimited width,
+// so every Group that can be flattened is
 func (doc Doc) String() string {
-	return strings.Join(doc.toLines(writeNothing, writeNothing), "\n")
+	return doc.Pretty(0)
+}
+
+// Renders the doc as plain text, breaking Groups that do not fit in the given width
+func (doc Doc) Pretty(width uint) string {
+	return doc.PrettyWith(Plain, width)
+}
+
+func (doc Doc) PrettyWith(renderer Renderer, width uint) string {
+	builder := strings.Builder{}
+	// Writing to a strings.Builder never fails
+	_ = doc.Render(&builder, RenderOptions{Renderer: renderer, Width: width})
+	return builder.String()
+}
+
+type RenderOptions struct {
+	// Plain when nil
+	Renderer Renderer
+	// Groups that do not fit in this many cells are broken, zero means unlimited
+	Width uint
+	// Rendering stops after this many lines, zero means unlimited
+	MaxLines uint
+	// Lines are cut after this many cells, zero means unlimited
+	MaxColumns uint
+}
+
+// Streams the rendered doc to w, without building its lines in memory
+func (doc Doc) Render(w io.Writer, opts RenderOptions) error {
+	out := &errWriter{writer: bufio.NewWriter(w)}
+	p := printer{
+		out:        out,
+		renderer:   opts.Renderer,
+		width:      opts.Width,
+		maxLines:   opts.MaxLines,
+		maxColumns: opts.MaxColumns,
+		// The doc starts at the start of a line
+		pending: true,
+	}
+	if p.renderer == nil {
+		p.renderer = Plain
+	}
+	if p.width == 0 {
+		p.width = math.MaxUint
+	}
+	p.render(doc.impl)
+	if out.err != nil {
+		return out.err
+	}
+	return out.writer.Flush()
+}
+
+// Keeps the first error, so that rendering can stop early
+type errWriter struct {
+	writer *bufio.Writer
+	err    error
 }
 
-func (doc Doc) toLines(writePrefix func(*strings.Builder), writeSuffix func(*strings.Builder)) []string {
-	return doc.impl.toLines(writePrefix, writeSuffix)
+func (w *errWriter) WriteString(s string) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.writer.WriteString(s)
+	w.err = err
+	return n, err
+}
+
+// Ambiguous width characters, like the box drawing ones, are measured as
+// narrow regardless of the locale, as tview does
+var widthCondition = &runewidth.Condition{EastAsianWidth: false, StrictEmojiNeutral: true}
+
+// Number of terminal cells taken by s, accounting for
+// wide characters and combining marks
+func Width(s string) uint {
+	return uint(widthCondition.StringWidth(s))
 }
 
 type prettyDocImpl interface {
-	toLines(writePrefix func(*strings.Builder), writeSuffix func(*strings.Builder)) []string
+	sealed()
 }
 
 func FromString(s string) Doc {
 	lines := strings.Split(s, "\n")
-	switch len(lines) {
-	case 0:
-		return Doc{lineDoc{line: ""}}
-	case 1:
-		return Doc{lineDoc{line: lines[0]}}
-	default:
-		lineDocs := make([]prettyDocImpl, 0, len(lines))
-		for i := 0; i < len(lines); i++ {
-			lineDocs = append(lineDocs, lineDoc{line: lines[i]})
+	if len(lines) == 1 {
+		return Doc{textDoc{text: lines[0]}}
+	}
+	items := make([]prettyDocImpl, 0, 2*len(lines)-1)
+	for i, line := range lines {
+		if i > 0 {
+			items = append(items, lineDoc{hard: true})
 		}
-		return Doc{sequenceDoc{items: lineDocs}}
+		items = append(items, textDoc{text: line})
 	}
+	return Doc{concatDoc{items: items}}
 }
 
+// Indents every line of the doc, including the first one when the doc
+// starts at the beginning of a line
 func Indent(indent uint, doc Doc) Doc {
-	return Doc{indentDoc{indent: indent, item: doc}}
+	return Doc{indentDoc{indent: indent, item: doc.impl}}
+}
+
+// Indents the lines started by line breaks within the doc
+func Nest(indent uint, doc Doc) Doc {
+	return Doc{indentDoc{indent: indent, nested: true, item: doc.impl}}
 }
 
 func PrefixLines(prefixes []string, doc Doc) Doc {
@@ -49,21 +129,21 @@ func PrefixLines(prefixes []string, doc Doc) Doc {
 	if len(prefixes) == 0 {
 		return doc
 	}
-	return Doc{linePrefixDoc{prefixes: prefixes, item: doc}}
+	return Doc{linePrefixDoc{prefixes: prefixes, item: doc.impl}}
 }
 
 func padPrefixes(prefixes []string) []string {
 	if len(prefixes) == 0 {
 		return nil
 	}
-	maxLen := 0
+	maxLen := uint(0)
 	for _, prefix := range prefixes {
-		if prefixLen := utf8.RuneCountInString(prefix); prefixLen > maxLen {
+		if prefixLen := Width(prefix); prefixLen > maxLen {
 			maxLen = prefixLen
 		}
 	}
 	pad := func(prefix string) string {
-		prefixLen := utf8.RuneCountInString(prefix)
+		prefixLen := Width(prefix)
 		if prefixLen == maxLen {
 			return prefix
 		}
@@ -71,7 +151,7 @@ func padPrefixes(prefixes []string) []string {
 		builder := strings.Builder{}
 		builder.WriteString(prefix)
 
-		for runesToAdd := maxLen - prefixLen; runesToAdd > 0; runesToAdd-- {
+		for cellsToAdd := maxLen - prefixLen; cellsToAdd > 0; cellsToAdd-- {
 			builder.WriteString(" ")
 		}
 		return builder.String()
@@ -83,198 +163,357 @@ func padPrefixes(prefixes []string) []string {
 	return prefixesCopy
 }
 
+// Stacks docs vertically, each one starting on a new line
 func Sequence(doc Doc, moreDocs ...Doc) Doc {
 	if len(moreDocs) == 0 {
 		return doc
 	}
-	items := make([]prettyDocImpl, 1+len(moreDocs))
-	items[0] = doc.impl
-	for i, doc := range moreDocs {
-		items[i+1] = doc.impl
+	items := make([]prettyDocImpl, 0, 1+2*len(moreDocs))
+	items = append(items, doc.impl)
+	for _, doc := range moreDocs {
+		items = append(items, lineDoc{hard: true}, doc.impl)
+	}
+	return Doc{concatDoc{items: items}}
+}
+
+// Places docs one after the other, in the same line
+func Concat(docs ...Doc) Doc {
+	items := make([]prettyDocImpl, 0, len(docs))
+	for _, doc := range docs {
+		items = append(items, doc.impl)
 	}
-	return Doc{sequenceDoc{items: items}}
+	return Doc{concatDoc{items: items}}
+}
+
+// A line break, or a space when flattened by a Group
+func Line() Doc {
+	return Doc{lineDoc{flat: " "}}
+}
+
+// A line break, or nothing when flattened by a Group
+func SoftLine() Doc {
+	return Doc{lineDoc{flat: ""}}
+}
+
+// A line break that is never flattened
+func HardLine() Doc {
+	return Doc{lineDoc{hard: true}}
+}
+
+// Renders the doc in a single line if it fits the width,
+// otherwise its Lines become line breaks
+func Group(doc Doc) Doc {
+	return Doc{groupDoc{item: doc.impl}}
+}
+
+func Annotate(annotation Annotation, doc Doc) Doc {
+	return Doc{annotationDoc{annotation: annotation, item: doc.impl}}
 }
 
 func Bold(doc Doc) Doc {
-	return Doc{escapeCodesDoc{codes: "1", resets: "22", item: doc}}
+	return Annotate(Strong, doc)
 }
 
 func Italic(doc Doc) Doc {
-	return Doc{escapeCodesDoc{codes: "3", resets: "23", item: doc}}
+	return Annotate(Emphasis, doc)
 }
 
-const (
-	ColorBlack uint8 = iota
-	ColorRed
-	ColorGreen
-	ColorYellow
-	ColorBlue
-	ColorMagenta
-	ColorCyan
-	ColorWhite
-	ColorDefault
-)
-
 func ForegroundColor(color uint8, doc Doc) Doc {
-	var code string
-	switch color {
-	case 0:
-		code = "30"
-	case 1:
-		code = "31"
-	case 2:
-		code = "32"
-	case 3:
-		code = "33"
-	case 4:
-		code = "34"
-	case 5:
-		code = "35"
-	case 6:
-		code = "36"
-	case 7:
-		code = "37"
-	default:
+	if color >= ColorDefault {
 		return doc
 	}
-	return Doc{escapeCodesDoc{codes: code, resets: "39", item: doc}}
+	return Annotate(ColorAnnotation(color), doc)
 }
 
-func TViewInvert(doc Doc) Doc {
-	return Doc{tviewStyleDoc{flags: "r", item: doc}}
+type textDoc struct {
+	text string
 }
 
 type lineDoc struct {
-	line string
+	// Rendered instead of the line break when flattened
+	flat string
+	hard bool
 }
 
-func (s lineDoc) toLines(writePrefix func(*strings.Builder), writeSuffix func(*strings.Builder)) []string {
-	builder := strings.Builder{}
-	writePrefix(&builder)
-	builder.WriteString(s.line)
-	writeSuffix(&builder)
-	return []string{builder.String()}
+type concatDoc struct {
+	items []prettyDocImpl
+}
+
+type groupDoc struct {
+	item prettyDocImpl
 }
 
 type indentDoc struct {
 	indent uint
+	nested bool
 	item   prettyDocImpl
 }
 
-func (i indentDoc) toLines(writePrefix func(*strings.Builder), writeSuffix func(*strings.Builder)) []string {
-	return i.item.toLines(
-		func(builder *strings.Builder) {
-			writePrefix(builder)
-			writeIndent(builder, i.indent)
-		}, writeSuffix,
-	)
-}
-
 type linePrefixDoc struct {
 	prefixes []string
 	item     prettyDocImpl
 }
 
-func (d linePrefixDoc) toLines(writePrefix func(*strings.Builder), writeSuffix func(*strings.Builder)) []string {
-	line := 0
-	lastPrefix := d.prefixes[len(d.prefixes)-1]
-	return d.item.toLines(
-		func(builder *strings.Builder) {
-			writePrefix(builder)
-			if line < len(d.prefixes) {
-				builder.WriteString(d.prefixes[line])
-			} else {
-				builder.WriteString(lastPrefix)
-			}
-			line++
-		},
-		writeSuffix,
-	)
+// Annotations are closed at the end of each line, and reopened after the
+// indentation and prefixes of the next one
+type annotationDoc struct {
+	annotation Annotation
+	item       prettyDocImpl
 }
 
-type sequenceDoc struct {
-	items []prettyDocImpl
+func (textDoc) sealed()       {}
+func (lineDoc) sealed()       {}
+func (concatDoc) sealed()     {}
+func (groupDoc) sealed()      {}
+func (indentDoc) sealed()     {}
+func (linePrefixDoc) sealed() {}
+func (annotationDoc) sealed() {}
+
+// Rendering
+
+type mode uint8
+
+const (
+	modeBreak mode = iota
+	modeFlat
+)
+
+type command struct {
+	mode mode
+	doc  prettyDocImpl
+	// Commands without a doc leave the innermost line writer
+	exit bool
 }
 
-func (seq sequenceDoc) toLines(writePrefix func(*strings.Builder), writeSuffix func(*strings.Builder)) []string {
-	lines := make([]string, 0, len(seq.items))
-	for i := 0; i < len(seq.items); i++ {
-		lines = append(lines, seq.items[i].toLines(writePrefix, writeSuffix)...)
+// Line writers are applied lazily when the first text of a line is written,
+// so that docs starting at the beginning of a line get their indentation
+type lineWriter struct {
+	firstLine  uint
+	indent     uint
+	prefixes   []string
+	prefixLine uint
+	annotated  bool
+}
+
+type printer struct {
+	out        *errWriter
+	renderer   Renderer
+	width      uint
+	maxLines   uint
+	maxColumns uint
+	col        uint
+	line       uint
+	pending    bool
+	done       bool
+	writers    []lineWriter
+	commands   []command
+	// Annotations of the annotated line writers, in the same order
+	annotations []Annotation
+	// Reused by fits, to avoid allocating for every Group
+	fitsStack []command
+}
+
+func (p *printer) render(doc prettyDocImpl) {
+	p.commands = append(p.commands, command{mode: modeBreak, doc: doc})
+	for len(p.commands) > 0 && !p.done && p.out.err == nil {
+		cmd := p.commands[len(p.commands)-1]
+		p.commands = p.commands[:len(p.commands)-1]
+		if cmd.exit {
+			p.exit()
+			continue
+		}
+		p.step(cmd)
 	}
-	return lines
 }
 
-type tviewStyleDoc struct {
-	foreground string
-	background string
-	flags      string
-	item       prettyDocImpl
+func (p *printer) step(cmd command) {
+	switch doc := cmd.doc.(type) {
+	case textDoc:
+		p.text(doc.text)
+	case lineDoc:
+		if cmd.mode == modeFlat && !doc.hard {
+			p.text(doc.flat)
+		} else {
+			p.newline()
+		}
+	case concatDoc:
+		for i := len(doc.items) - 1; i >= 0; i-- {
+			p.commands = append(p.commands, command{mode: cmd.mode, doc: doc.items[i]})
+		}
+	case groupDoc:
+		groupMode := modeBreak
+		if cmd.mode == modeFlat || p.fits(doc.item) {
+			groupMode = modeFlat
+		}
+		p.commands = append(p.commands, command{mode: groupMode, doc: doc.item})
+	case indentDoc:
+		firstLine := p.line
+		if doc.nested || !p.pending {
+			firstLine++
+		}
+		p.enter(cmd.mode, doc.item, lineWriter{firstLine: firstLine, indent: doc.indent})
+	case linePrefixDoc:
+		firstLine := p.line
+		if !p.pending {
+			firstLine++
+		}
+		p.enter(cmd.mode, doc.item, lineWriter{firstLine: firstLine, prefixes: doc.prefixes})
+	case annotationDoc:
+		p.annotations = append(p.annotations, doc.annotation)
+		if !p.pending {
+			p.renderer.Open(p.out, p.annotations)
+		}
+		p.enter(cmd.mode, doc.item, lineWriter{firstLine: p.line, annotated: true})
+	default:
+		panic("unknown doc")
+	}
 }
 
-func (d tviewStyleDoc) toLines(writePrefix func(*strings.Builder), writeSuffix func(*strings.Builder)) []string {
-	return d.item.toLines(
-		func(builder *strings.Builder) {
-			writePrefix(builder)
-			builder.WriteString("[")
-			builder.WriteString(d.foreground)
-			builder.WriteString(":")
-			builder.WriteString(d.background)
-			builder.WriteString(":")
-			builder.WriteString(d.flags)
-			builder.WriteString("]")
-		},
-		func(builder *strings.Builder) {
-			builder.WriteString("[")
-			if d.foreground != "" {
-				builder.WriteString("-")
-			}
-			builder.WriteString(":")
-			if d.background != "" {
-				builder.WriteString("-")
-			}
-			builder.WriteString(":")
-			if d.flags != "" {
-				builder.WriteString(strings.ToUpper(d.flags))
-			}
-			builder.WriteString("]")
-			writeSuffix(builder)
-		},
-	)
+func (p *printer) enter(mode mode, doc prettyDocImpl, writer lineWriter) {
+	p.writers = append(p.writers, writer)
+	p.commands = append(p.commands, command{exit: true}, command{mode: mode, doc: doc})
 }
 
-type escapeCodesDoc struct {
-	codes  string
-	resets string
-	item   prettyDocImpl
+func (p *printer) exit() {
+	writer := p.writers[len(p.writers)-1]
+	p.writers = p.writers[:len(p.writers)-1]
+	if writer.annotated {
+		if !p.pending {
+			p.renderer.Close(p.out, p.annotations)
+		}
+		p.annotations = p.annotations[:len(p.annotations)-1]
+	}
 }
 
-func (d escapeCodesDoc) toLines(writePrefix func(*strings.Builder), writeSuffix func(*strings.Builder)) []string {
-	return d.item.toLines(
-		func(builder *strings.Builder) {
-			writePrefix(builder)
-			writeEscapeCodes(builder, d.codes)
-		},
-		func(builder *strings.Builder) {
-			writeEscapeCodes(builder, d.resets)
-			writeSuffix(builder)
-		},
-	)
+func (p *printer) text(text string) {
+	if p.pending {
+		p.startLine()
+	}
+	p.write(text)
 }
 
-func writeEscapeCodes(builder io.StringWriter, codes string) {
-	if len(codes) == 0 {
+// Writes text in the current line, cutting it at maxColumns
+func (p *printer) write(text string) {
+	width := Width(text)
+	if p.maxColumns > 0 && p.col+width > p.maxColumns {
+		if p.col < p.maxColumns {
+			p.renderer.Text(p.out, widthCondition.Truncate(text, int(p.maxColumns-p.col), ""))
+		}
+		p.col += width
 		return
 	}
-	builder.WriteString("\u001b[")
-	builder.WriteString(codes)
-	builder.WriteString("m")
+	p.renderer.Text(p.out, text)
+	p.col += width
 }
 
-func writeNothing(_ *strings.Builder) {}
+func (p *printer) startLine() {
+	p.pending = false
+	annotations := 0
+	for i := range p.writers {
+		writer := &p.writers[i]
+		if writer.annotated {
+			annotations++
+		}
+		if writer.firstLine > p.line {
+			continue
+		}
+		switch {
+		case writer.annotated:
+			p.renderer.Open(p.out, p.annotations[:annotations])
+		case writer.prefixes != nil:
+			prefix := writer.prefixes[min(writer.prefixLine, uint(len(writer.prefixes)-1))]
+			writer.prefixLine++
+			p.write(prefix)
+		default:
+			for indent := writer.indent; indent > 0; {
+				chunk := min(indent, uint(len(spaces)))
+				p.write(spaces[:chunk])
+				indent -= chunk
+			}
+		}
+	}
+}
 
-func writeIndent(builder *strings.Builder, indent uint) {
-	builder.Grow(int(indent))
-	for i := uint(0); i < indent; i++ {
-		builder.WriteString(" ")
+func (p *printer) newline() {
+	if p.pending {
+		p.startLine()
+	}
+	for i := len(p.annotations); i > 0; i-- {
+		p.renderer.Close(p.out, p.annotations[:i])
 	}
+	if p.maxLines > 0 && p.line+1 >= p.maxLines {
+		p.done = true
+		return
+	}
+	p.out.WriteString("\n")
+	p.line++
+	p.col = 0
+	p.pending = true
 }
+
+// Whether the doc fits in the rest of the current line when flattened,
+// along with whatever follows it up to the next line break
+func (p *printer) fits(doc prettyDocImpl) bool {
+	if p.width == math.MaxUint {
+		return true
+	}
+	col := p.col
+	if p.pending {
+		for _, writer := range p.writers {
+			if writer.firstLine > p.line {
+				continue
+			}
+			col += writer.indent
+			if writer.prefixes != nil {
+				prefix := writer.prefixes[min(writer.prefixLine, uint(len(writer.prefixes)-1))]
+				col += Width(prefix)
+			}
+		}
+	}
+	if col > p.width {
+		return false
+	}
+	remaining := int(p.width - col)
+
+	stack := append(p.fitsStack[:0], command{mode: modeFlat, doc: doc})
+	defer func() { p.fitsStack = stack[:0] }()
+	rest := len(p.commands)
+	for remaining >= 0 {
+		if len(stack) == 0 {
+			if rest == 0 {
+				return true
+			}
+			rest--
+			stack = append(stack, p.commands[rest])
+		}
+		cmd := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if cmd.exit {
+			continue
+		}
+		switch doc := cmd.doc.(type) {
+		case textDoc:
+			remaining -= int(Width(doc.text))
+		case lineDoc:
+			if cmd.mode == modeBreak || doc.hard {
+				return cmd.mode == modeBreak
+			}
+			remaining -= int(Width(doc.flat))
+		case concatDoc:
+			for i := len(doc.items) - 1; i >= 0; i-- {
+				stack = append(stack, command{mode: cmd.mode, doc: doc.items[i]})
+			}
+		case groupDoc:
+			stack = append(stack, command{mode: cmd.mode, doc: doc.item})
+		case indentDoc:
+			stack = append(stack, command{mode: cmd.mode, doc: doc.item})
+		case linePrefixDoc:
+			stack = append(stack, command{mode: cmd.mode, doc: doc.item})
+		case annotationDoc:
+			stack = append(stack, command{mode: cmd.mode, doc: doc.item})
+		}
+	}
+	return false
+}
+
+const spaces = "                                "
@@ -0,0 +1,68 @@
+package pretty
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderLimits(t *testing.T) {
+	doc := Sequence(FromString("alpha"), Indent(40, FromString("beta")), FromString("gamma"))
+	cases := []struct {
+		name     string
+		options  RenderOptions
+		expected string
+	}{
+		{"unlimited", RenderOptions{}, "alpha\n" + strings.Repeat(" ", 40) + "beta\ngamma"},
+		{"max lines", RenderOptions{MaxLines: 2}, "alpha\n" + strings.Repeat(" ", 40) + "beta"},
+		{"max columns", RenderOptions{MaxColumns: 3}, "alp\n   \ngam"},
+	}
+	for _, c := range cases {
+		builder := strings.Builder{}
+		if err := doc.Render(&builder, c.options); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if builder.String() != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.name, c.expected, builder.String())
+		}
+	}
+}
+
+func TestLayout(t *testing.T) {
+	text := FromString
+	call := Group(Concat(text("f"), Nest(2, Concat(Line(), text("alpha"), Line(), text("beta")))))
+	parens := Group(Concat(text("("), Nest(1, Concat(SoftLine(), text("x"))), SoftLine(), text(")")))
+	nestedGroups := Group(Concat(
+		text("a"),
+		Nest(2, Concat(Line(), Group(Concat(text("b"), Line(), text("c"))), Line(), text("ddddddddd"))),
+	))
+	cases := []struct {
+		name     string
+		doc      Doc
+		width    uint
+		expected string
+	}{
+		{"unlimited width flattens", call, 0, "f alpha beta"},
+		{"fits exactly", call, 12, "f alpha beta"},
+		{"one cell short breaks every line", call, 11, "f\n  alpha\n  beta"},
+		{"soft lines vanish when flat", parens, 3, "(x)"},
+		{"soft lines break", parens, 2, "(\n x\n)"},
+		{"inner group stays flat", nestedGroups, 8, "a\n  b c\n  ddddddddd"},
+		{"inner group breaks too", nestedGroups, 2, "a\n  b\n  c\n  ddddddddd"},
+		{"text after the group counts", Concat(Group(Concat(text("aa"), Line(), text("bb"))), text("cccc")), 6, "aa\nbbcccc"},
+		{"hard lines break flat groups", Group(Concat(text("a"), HardLine(), text("b"), Line(), text("c"))), 0, "a\nb c"},
+		{
+			"nested indentation adds up",
+			Nest(2, Concat(text("a"), HardLine(), Nest(2, Concat(text("b"), HardLine(), text("c"))), HardLine(), text("d"))),
+			0, "a\n  b\n    c\n  d",
+		},
+		{"indent applies to the first line", Indent(2, Concat(text("a"), HardLine(), text("b"))), 0, "  a\n  b"},
+		{"nest does not", Nest(2, Concat(text("a"), HardLine(), text("b"))), 0, "a\n  b"},
+		{"wide characters take two cells", Group(Concat(text("λλ"), Line(), text("漢字"))), 7, "λλ 漢字"},
+		{"wide characters break earlier", Group(Concat(text("λλ"), Line(), text("漢字"))), 6, "λλ\n漢字"},
+	}
+	for _, c := range cases {
+		if actual := c.doc.Pretty(c.width); actual != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.name, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package pretty
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// Annotations say what a piece of a doc is,
+// and each Renderer decides how that looks in its output
+
+type Annotation string
+
+const (
+	Highlight Annotation = "highlight"
+	Binder    Annotation = "binder"
+	BoundVar  Annotation = "bound-var"
+	FreeVar   Annotation = "free-var"
+	Redex     Annotation = "redex"
+	Elided    Annotation = "elided"
+	Argument  Annotation = "argument"
+	Reduct    Annotation = "reduct"
+	Removed   Annotation = "removed"
+	Added     Annotation = "added"
+	Strong    Annotation = "strong"
+	Emphasis  Annotation = "emphasis"
+)
+
+const (
+	ColorBlack uint8 = iota
+	ColorRed
+	ColorGreen
+	ColorYellow
+	ColorBlue
+	ColorMagenta
+	ColorCyan
+	ColorWhite
+	ColorDefault
+)
+
+var colorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+func ColorAnnotation(color uint8) Annotation {
+	return Annotation("color-" + colorNames[color])
+}
+
+const regionPrefix = "region:"
+
+// A region of text that renderers can let users point at, like tview regions.
+// IDs should match [a-zA-Z0-9_,;: \-\.]+ to work everywhere.
+func RegionAnnotation(id string) Annotation {
+	return Annotation(regionPrefix + id)
+}
+
+func RegionID(annotation Annotation) (string, bool) {
+	return strings.CutPrefix(string(annotation), regionPrefix)
+}
+
+// The ID of the innermost region, or "" outside of all regions
+func innermostRegion(annotations []Annotation) string {
+	for _, annotation := range slices.Backward(annotations) {
+		if id, ok := RegionID(annotation); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+type Renderer interface {
+	Text(out io.StringWriter, text string)
+	// Called with every annotation in effect, innermost last,
+	// whenever the innermost one starts applying to the text
+	Open(out io.StringWriter, annotations []Annotation)
+	// Called with every annotation in effect, innermost last,
+	// whenever the innermost one stops applying to the text
+	Close(out io.StringWriter, annotations []Annotation)
+}
+
+// Styles for renderers that draw annotations directly
+type Style struct {
+	Bold      bool
+	Italic    bool
+	Underline bool
+	Reverse   bool
+	// ColorDefault keeps the color of the surrounding text
+	Foreground uint8
+}
+
+type Theme map[Annotation]Style
+
+var DefaultTheme = func() Theme {
+	theme := Theme{
+		Highlight: {Reverse: true, Foreground: ColorDefault},
+		Redex:     {Reverse: true, Foreground: ColorDefault},
+		Elided:    {Italic: true, Foreground: ColorMagenta},
+		Argument:  {Bold: true, Foreground: ColorBlue},
+		Reduct:    {Underline: true, Foreground: ColorDefault},
+		Removed:   {Foreground: ColorRed},
+		Added:     {Foreground: ColorGreen},
+		Binder:    {Bold: true, Foreground: ColorCyan},
+		BoundVar:  {Foreground: ColorCyan},
+		FreeVar:   {Foreground: ColorYellow},
+		Strong:    {Bold: true, Foreground: ColorDefault},
+		Emphasis:  {Italic: true, Foreground: ColorDefault},
+	}
+	for color := range uint8(len(colorNames)) {
+		theme[ColorAnnotation(color)] = Style{Foreground: color}
+	}
+	return theme
+}()
+
+// Combines the styles of the annotations, inner ones taking precedence
+func (theme Theme) styleOf(annotations []Annotation) Style {
+	combined := Style{Foreground: ColorDefault}
+	for _, annotation := range annotations {
+		style, found := theme[annotation]
+		if !found {
+			continue
+		}
+		combined.Bold = combined.Bold || style.Bold
+		combined.Italic = combined.Italic || style.Italic
+		combined.Underline = combined.Underline || style.Underline
+		combined.Reverse = combined.Reverse || style.Reverse
+		if style.Foreground < ColorDefault {
+			combined.Foreground = style.Foreground
+		}
+	}
+	return combined
+}
+
+// Plain text
+
+var Plain Renderer = plainRenderer{}
+
+type plainRenderer struct{}
+
+func (plainRenderer) Text(out io.StringWriter, text string)   { out.WriteString(text) }
+func (plainRenderer) Open(_ io.StringWriter, _ []Annotation)  {}
+func (plainRenderer) Close(_ io.StringWriter, _ []Annotation) {}
+
+// Plain text, with the given annotations drawn as text around what they apply to,
+// e.g. {Redex: {"⟦", "⟧"}}. Annotations are drawn again on each line they span.
+func Markers(markers map[Annotation][2]string) Renderer {
+	return markersRenderer{markers}
+}
+
+type markersRenderer struct{ markers map[Annotation][2]string }
+
+func (r markersRenderer) Text(out io.StringWriter, text string) { out.WriteString(text) }
+
+func (r markersRenderer) Open(out io.StringWriter, annotations []Annotation) {
+	if marker, found := r.markers[annotations[len(annotations)-1]]; found {
+		out.WriteString(marker[0])
+	}
+}
+
+func (r markersRenderer) Close(out io.StringWriter, annotations []Annotation) {
+	if marker, found := r.markers[annotations[len(annotations)-1]]; found {
+		out.WriteString(marker[1])
+	}
+}
+
+// ANSI terminals
+
+func ANSI(theme Theme) Renderer {
+	return ansiRenderer{theme}
+}
+
+type ansiRenderer struct{ theme Theme }
+
+func (r ansiRenderer) Text(out io.StringWriter, text string) { out.WriteString(text) }
+
+func (r ansiRenderer) Open(out io.StringWriter, annotations []Annotation) {
+	writeEscapeCodes(out, r.theme.styleOf(annotations[len(annotations)-1:]))
+}
+
+func (r ansiRenderer) Close(out io.StringWriter, annotations []Annotation) {
+	out.WriteString("\u001b[0m")
+	writeEscapeCodes(out, r.theme.styleOf(annotations[:len(annotations)-1]))
+}
+
+func writeEscapeCodes(out io.StringWriter, style Style) {
+	codes := []string{}
+	if style.Bold {
+		codes = append(codes, "1")
+	}
+	if style.Italic {
+		codes = append(codes, "3")
+	}
+	if style.Underline {
+		codes = append(codes, "4")
+	}
+	if style.Reverse {
+		codes = append(codes, "7")
+	}
+	if style.Foreground < ColorDefault {
+		codes = append(codes, fmt.Sprint(30+style.Foreground))
+	}
+	if len(codes) == 0 {
+		return
+	}
+	out.WriteString("\u001b[")
+	out.WriteString(strings.Join(codes, ";"))
+	out.WriteString("m")
+}
+
+// tview TextViews with dynamic colors
+
+func TView(theme Theme) Renderer {
+	return tviewRenderer{theme}
+}
+
+type tviewRenderer struct{ theme Theme }
+
+func (r tviewRenderer) Text(out io.StringWriter, text string) {
+	if !strings.Contains(text, "[") {
+		out.WriteString(text)
+		return
+	}
+	out.WriteString(tview.Escape(text))
+}
+
+func (r tviewRenderer) Open(out io.StringWriter, annotations []Annotation) {
+	if id, ok := RegionID(annotations[len(annotations)-1]); ok {
+		writeRegionTag(out, id)
+		return
+	}
+	writeStyleTag(out, r.theme.styleOf(annotations))
+}
+
+func (r tviewRenderer) Close(out io.StringWriter, annotations []Annotation) {
+	if _, ok := RegionID(annotations[len(annotations)-1]); ok {
+		writeRegionTag(out, innermostRegion(annotations[:len(annotations)-1]))
+		return
+	}
+	writeStyleTag(out, r.theme.styleOf(annotations[:len(annotations)-1]))
+}
+
+// Regions do not nest, so closing one reopens the region around it
+func writeRegionTag(out io.StringWriter, id string) {
+	out.WriteString(`["`)
+	out.WriteString(id)
+	out.WriteString(`"]`)
+}
+
+// Tags are always written for the whole style, so that closing an
+// annotation restores the style of the ones around it
+func writeStyleTag(out io.StringWriter, style Style) {
+	foreground := "-"
+	if style.Foreground < ColorDefault {
+		foreground = colorNames[style.Foreground]
+	}
+	flags := ""
+	if style.Bold {
+		flags += "b"
+	}
+	if style.Italic {
+		flags += "i"
+	}
+	if style.Underline {
+		flags += "u"
+	}
+	if style.Reverse {
+		flags += "r"
+	}
+	// Flags are turned on one by one, so first reset them all
+	out.WriteString("[" + foreground + ":-:-]")
+	if flags != "" {
+		out.WriteString("[::" + flags + "]")
+	}
+}
+
+// HTML, with a CSS class for each annotation
+
+var HTML Renderer = htmlRenderer{}
+
+type htmlRenderer struct{}
+
+func (htmlRenderer) Text(out io.StringWriter, text string) {
+	out.WriteString(html.EscapeString(text))
+}
+
+func (htmlRenderer) Open(out io.StringWriter, annotations []Annotation) {
+	if id, ok := RegionID(annotations[len(annotations)-1]); ok {
+		out.WriteString(`<span data-region="` + html.EscapeString(id) + `">`)
+		return
+	}
+	out.WriteString(`<span class="` + HTMLClass(annotations[len(annotations)-1]) + `">`)
+}
+
+func (htmlRenderer) Close(out io.StringWriter, _ []Annotation) {
+	out.WriteString("</span>")
+}
+
+func HTMLClass(annotation Annotation) string {
+	return "lambda-" + string(annotation)
+}
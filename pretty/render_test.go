@@ -0,0 +1,45 @@
+package pretty
+
+import "testing"
+
+// A redex around a region around a two-line argument, with characters
+// that need escaping
+func annotatedDoc() Doc {
+	argument := Annotate(Argument, Sequence(FromString("a<b"), FromString("[c] & d")))
+	return Annotate(Redex, Concat(
+		FromString("f "),
+		Annotate(RegionAnnotation("/1"), argument),
+		FromString(" e"),
+	))
+}
+
+func TestRenderers(t *testing.T) {
+	cases := []struct {
+		name     string
+		renderer Renderer
+		expected string
+	}{
+		{"plain", Plain, "f a<b\n[c] & d e"},
+		{"markers", Markers(map[Annotation][2]string{Redex: {"⟦", "⟧"}, Argument: {"<", ">"}}), "⟦f <a<b>⟧\n⟦<[c] & d> e⟧"},
+		{
+			"ansi", ANSI(DefaultTheme),
+			"\x1b[7mf \x1b[1;34ma<b\x1b[0m\x1b[7m\x1b[0m\x1b[7m\x1b[0m\n" +
+				"\x1b[7m\x1b[1;34m[c] & d\x1b[0m\x1b[7m\x1b[0m\x1b[7m e\x1b[0m",
+		},
+		{
+			"tview", TView(DefaultTheme),
+			`[-:-:-][::r]f ["/1"][blue:-:-][::br]a<b[-:-:-][::r][""][-:-:-]` + "\n" +
+				`[-:-:-][::r]["/1"][blue:-:-][::br][c[] & d[-:-:-][::r][""] e[-:-:-]`,
+		},
+		{
+			"html", HTML,
+			`<span class="lambda-redex">f <span data-region="/1"><span class="lambda-argument">a&lt;b</span></span></span>` + "\n" +
+				`<span class="lambda-redex"><span data-region="/1"><span class="lambda-argument">[c] &amp; d</span></span> e</span>`,
+		},
+	}
+	for _, c := range cases {
+		if actual := annotatedDoc().PrettyWith(c.renderer, 0); actual != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.name, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Saved state of an interactive session, so that it can be resumed later
+// or shared with someone else
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/gusbicalho/go-lambda/format"
+	"github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
+	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless_to_parse_tree"
+	"github.com/gusbicalho/go-lambda/parse_tree"
+	"github.com/gusbicalho/go-lambda/parse_tree_to_locally_nameless"
+	"github.com/gusbicalho/go-lambda/parser"
+	"github.com/gusbicalho/go-lambda/tokenizer"
+)
+
+// Terms are kept in lambda notation, so that the file can be read and edited by hand
+type Session struct {
+	// What the session started from
+	Source string `json:"source"`
+	// Top-level lets of the source, for readers of the file. They are not
+	// replayed on load, since the terms already hold them as applied lambdas
+	Definitions []Definition `json:"definitions,omitempty"`
+	Term        string       `json:"term"`
+	// Every term the session went through, ending with the current one
+	History  []string `json:"history"`
+	Focus    string   `json:"focus"`
+	Strategy string   `json:"strategy"`
+	MaxDepth uint     `json:"maxDepth,omitempty"`
+}
+
+type Definition struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func New(source string) (Session, error) {
+	tree, err := parser.Parse(tokenizer.New(strings.NewReader(source)))
+	if err != nil {
+		return Session{}, err
+	}
+	term := Notation(parse_tree_to_locally_nameless.ToLocallyNameless(*tree))
+	return Session{
+		Source:      source,
+		Definitions: definitions(*tree),
+		Term:        term,
+		History:     []string{term},
+		Focus:       ln.Path{}.String(),
+		Strategy:    beta_reduce.NormalOrder.String(),
+	}, nil
+}
+
+// Binders are renamed where needed, so that parsing the notation gives back the same term
+func Notation(e ln.Expr) string {
+	return oneLine(locally_nameless_to_parse_tree.FromLocallyNameless(e))
+}
+
+func oneLine(tree parse_tree.ParseTree) string {
+	return strings.TrimSuffix(format.FormatTree(tree, math.MaxUint), "\n")
+}
+
+func Parse(notation string) (ln.Expr, error) {
+	tree, err := parser.Parse(tokenizer.New(strings.NewReader(notation)))
+	if err != nil {
+		return nil, err
+	}
+	return parse_tree_to_locally_nameless.ToLocallyNameless(*tree), nil
+}
+
+func definitions(tree parse_tree.ParseTree) []Definition {
+	defs := []Definition{}
+	for {
+		switch item := tree.Item.(type) {
+		case parse_tree.Parens:
+			tree = item.Child
+		case parse_tree.Let:
+			defs = append(defs, Definition{item.Name, oneLine(item.Value)})
+			tree = item.Body
+		default:
+			return defs
+		}
+	}
+}
+
+func Read(r io.Reader) (Session, error) {
+	var session Session
+	if err := json.NewDecoder(r).Decode(&session); err != nil {
+		return Session{}, err
+	}
+	if len(session.History) == 0 || session.History[len(session.History)-1] != session.Term {
+		return Session{}, errors.New("Invalid session, expected the history to end with the current term")
+	}
+	return session, nil
+}
+
+func (session Session) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(session)
+}
+
+// The parsed history, ending with the current term
+func (session Session) Terms() ([]ln.Expr, error) {
+	terms := make([]ln.Expr, len(session.History))
+	for i, notation := range session.History {
+		term, err := Parse(notation)
+		if err != nil {
+			return nil, errors.New(fmt.Sprint("History entry ", i, ": ", err))
+		}
+		terms[i] = term
+	}
+	return terms, nil
+}
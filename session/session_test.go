@@ -0,0 +1,46 @@
+package session
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+)
+
+func TestRoundTrip(t *testing.T) {
+	original, err := New("let id = \\x. x in let k = \\x. \\y. x in k id z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := []string{original.Definitions[0].Name, original.Definitions[1].Name}; !slices.Equal(names, []string{"id", "k"}) {
+		t.Errorf("Expected definitions id and k, got %v", names)
+	}
+	// The binder would capture the free x if it kept its name
+	captured := ln.NewApp(ln.NewLambda("x", ln.NewApp(ln.NewBound(0), ln.NewFree("x"))), ln.NewFree("x"))
+	original.Term = Notation(captured)
+	original.History = append(original.History, original.Term)
+	original.Focus = "/0/0"
+
+	buffer := bytes.Buffer{}
+	if err := original.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(read.Definitions, original.Definitions) {
+		t.Errorf("Expected definitions %v, got %v", original.Definitions, read.Definitions)
+	}
+	if read.Source != original.Source || read.Focus != original.Focus || !slices.Equal(read.History, original.History) {
+		t.Errorf("Expected %+v, got %+v", original, read)
+	}
+	terms, err := read.Terms()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := ln.ToLambdaNotation(terms[1], ln.DisplayDeBruijn); actual != ln.ToLambdaNotation(captured, ln.DisplayDeBruijn) {
+		t.Errorf("Expected %s, got %s", ln.ToLambdaNotation(captured, ln.DisplayDeBruijn), actual)
+	}
+}
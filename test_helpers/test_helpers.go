@@ -0,0 +1,52 @@
+// Helpers shared by the tests of other packages
+package test_helpers
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/parse_tree"
+	"github.com/gusbicalho/go-lambda/parse_tree_to_locally_nameless"
+	"github.com/gusbicalho/go-lambda/parser"
+	"github.com/gusbicalho/go-lambda/tokenizer"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// Fails the test when the source does not parse
+func Parse(t *testing.T, source string) parse_tree.ParseTree {
+	t.Helper()
+	tree, err := parser.Parse(tokenizer.New(strings.NewReader(source)))
+	if err != nil {
+		t.Fatalf("%s\n%s", err, source)
+	}
+	return *tree
+}
+
+func ParseExpr(t *testing.T, source string) expr.Expr {
+	t.Helper()
+	return parse_tree_to_locally_nameless.ToLocallyNameless(Parse(t, source))
+}
+
+// Compares with testdata/<name>.golden, or writes it when tests run with -update
+func Golden(t *testing.T, name string, actual string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual != string(expected) {
+		t.Errorf("%s - Expected:\n%s\nActual:\n%s", name, expected, actual)
+	}
+}
@@ -16,6 +16,9 @@ const (
 	Lambda
 	Dot
 	Identifier
+	Let
+	In
+	Equals
 )
 
 func (t Type) String() string {
@@ -34,6 +37,12 @@ func (t Type) String() string {
 		return "DOT"
 	case Identifier:
 		return "IDENT"
+	case Let:
+		return "LET"
+	case In:
+		return "IN"
+	case Equals:
+		return "EQUALS"
 	default:
 		return "UNKNOWN"
 	}
@@ -43,6 +52,8 @@ type Token struct {
 	tokenType Type
 	Value     string
 	Position  position.Position
+	// Text of the line comments preceding the token, without the leading #
+	Comments []string
 }
 
 func (t Token) String() string {
@@ -80,3 +91,15 @@ func DotToken(pos position.Position) Token {
 func IdentifierToken(name string, pos position.Position) Token {
 	return Token{tokenType: Identifier, Value: name, Position: pos}
 }
+
+func LetToken(pos position.Position) Token {
+	return Token{tokenType: Let, Value: "let", Position: pos}
+}
+
+func InToken(pos position.Position) Token {
+	return Token{tokenType: In, Value: "in", Position: pos}
+}
+
+func EqualsToken(pos position.Position) Token {
+	return Token{tokenType: Equals, Value: "=", Position: pos}
+}
@@ -2,15 +2,18 @@ package tokenizer
 
 import (
 	"io"
+	"strings"
 	"unicode"
 
+	"github.com/gusbicalho/go-lambda/position"
 	"github.com/gusbicalho/go-lambda/runes_reader"
 	"github.com/gusbicalho/go-lambda/token"
 )
 
 type Tokenizer struct {
-	runes  *runes_reader.RunesReader
-	buffer []token.Token
+	runes           *runes_reader.RunesReader
+	buffer          []token.Token
+	pendingComments []string
 }
 
 func New(r io.Reader) *Tokenizer {
@@ -47,17 +50,41 @@ func (t *Tokenizer) Peek() token.Token {
 }
 
 func (t *Tokenizer) nextFromRunes() token.Token {
+	tok := t.readToken()
+	comments := append(t.pendingComments, tok.Comments...)
+	t.pendingComments = nil
+	switch tok.Type() {
+	case token.RightParen, token.Dot, token.Equals, token.In:
+		// These tokens never start a term, so their comments
+		// are carried over to the next token
+		tok.Comments = nil
+		t.pendingComments = comments
+	default:
+		tok.Comments = comments
+	}
+	return tok
+}
+
+func (t *Tokenizer) readToken() token.Token {
 	pos := t.runes.Pos()
 
-	if err := t.skipWhitespace(); err != nil {
+	comments, err := t.skipTrivia()
+	if err != nil {
 		if err == io.EOF {
-			return token.EOFToken(pos)
+			tok := token.EOFToken(pos)
+			tok.Comments = comments
+			return tok
 		}
 		return token.InvalidToken(err.Error(), pos)
 	}
 
 	pos = t.runes.Pos()
+	tok := t.readTokenAt(pos)
+	tok.Comments = comments
+	return tok
+}
 
+func (t *Tokenizer) readTokenAt(pos position.Position) token.Token {
 	r, err := t.runes.Peek()
 	if err != nil {
 		if err == io.EOF {
@@ -79,12 +106,21 @@ func (t *Tokenizer) nextFromRunes() token.Token {
 	case '.':
 		t.runes.Consume()
 		return token.DotToken(pos)
+	case '=':
+		t.runes.Consume()
+		return token.EqualsToken(pos)
 	default:
 		if unicode.IsLetter(r) || r == '_' {
 			value, err := t.readIdentifier()
 			if err != nil {
 				return token.InvalidToken(err.Error(), pos)
 			}
+			switch value {
+			case "let":
+				return token.LetToken(pos)
+			case "in":
+				return token.InToken(pos)
+			}
 			return token.IdentifierToken(value, pos)
 		}
 
@@ -93,20 +129,45 @@ func (t *Tokenizer) nextFromRunes() token.Token {
 	}
 }
 
-func (t *Tokenizer) skipWhitespace() error {
+// Skips whitespace and line comments, returning the text of the comments
+func (t *Tokenizer) skipTrivia() ([]string, error) {
+	var comments []string
 	for {
 		r, err := t.runes.Peek()
 		if err != nil {
-			return err
+			return comments, err
 		}
 
-		if !unicode.IsSpace(r) {
-			break
+		switch {
+		case unicode.IsSpace(r):
+			t.runes.Consume()
+		case r == '#':
+			t.runes.Consume()
+			comment, err := t.readLine()
+			comments = append(comments, comment)
+			if err != nil {
+				return comments, err
+			}
+		default:
+			return comments, nil
 		}
+	}
+}
+
+func (t *Tokenizer) readLine() (string, error) {
+	var result []rune
 
+	for {
+		r, err := t.runes.Peek()
+		if err != nil {
+			return strings.TrimRightFunc(string(result), unicode.IsSpace), err
+		}
 		t.runes.Consume()
+		if r == '\n' {
+			return strings.TrimRightFunc(string(result), unicode.IsSpace), nil
+		}
+		result = append(result, r)
 	}
-	return nil
 }
 
 func (t *Tokenizer) readIdentifier() (string, error) {
@@ -121,7 +182,7 @@ func (t *Tokenizer) readIdentifier() (string, error) {
 			return "", err
 		}
 
-		if !unicode.IsLetter(r) && r != '_' {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) && r != '_' {
 			break
 		}
 
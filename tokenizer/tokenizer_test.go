@@ -0,0 +1,21 @@
+package tokenizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/token"
+)
+
+// Digits may follow the first letter, so that renamed binders like x_0 parse back
+func TestIdentifiersWithDigits(t *testing.T) {
+	expected := []string{"LAMBDA \\", "IDENT x_0", "DOT .", "IDENT x1", "INVALID 2", "IDENT x", "EOF "}
+	actual := []string{}
+	New(strings.NewReader("\\x_0. x1 2x")).Each(func(tok token.Token) error {
+		actual = append(actual, tok.Type().String()+" "+tok.Value)
+		return nil
+	})
+	if strings.Join(actual, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("Expected: %v\nActual:   %v", expected, actual)
+	}
+}
@@ -0,0 +1,467 @@
+// An interactive navigator for lambda terms. The Model holds the term and
+// the focus, and the App draws it and turns keys into model updates.
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/gusbicalho/go-lambda/keymap"
+	"github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
+	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/locally_nameless/metrics"
+	ln_pretty "github.com/gusbicalho/go-lambda/locally_nameless/pretty"
+	"github.com/gusbicalho/go-lambda/locally_nameless/rewrite"
+	"github.com/gusbicalho/go-lambda/locally_nameless/walk"
+	"github.com/gusbicalho/go-lambda/parse_tree"
+	"github.com/gusbicalho/go-lambda/parse_tree_to_locally_nameless"
+	"github.com/gusbicalho/go-lambda/parser"
+	"github.com/gusbicalho/go-lambda/pretty"
+	"github.com/gusbicalho/go-lambda/session"
+	"github.com/gusbicalho/go-lambda/tokenizer"
+
+	"github.com/rivo/tview"
+)
+
+var renderer = pretty.TView(pretty.DefaultTheme)
+
+type Options struct {
+	Keys keymap.Keymap
+	// Runs take Speed steps per second
+	Speed float64
+	// Where the session is saved on quit, if anywhere
+	SessionPath string
+}
+
+type App struct {
+	model       *Model
+	keys        keymap.Keymap
+	speed       float64
+	sessionPath string
+
+	app       *tview.Application
+	pages     *tview.Pages
+	header    *exprView
+	tree      *exprView
+	redexList *tview.List
+	history   *tview.TextView
+	status    *tview.TextView
+	editor    *tview.InputField
+	helpHint  string
+
+	redexes []beta_reduce.BetaRedex
+	// Set while the redex list is filled, so that it does not move the focus
+	listing bool
+	// Steps run on the application's goroutine; closing stopRun ends the ticker
+	running bool
+	stopRun chan struct{}
+	// Shown in the status line until the next key
+	notice string
+	// Called when the prompt is confirmed, and kept open while it fails
+	onPrompt func(text string) error
+}
+
+// Resumes the saved session, with undo back through its history
+func New(saved session.Session, options Options) (*App, error) {
+	model, err := NewModel(saved)
+	if err != nil {
+		return nil, err
+	}
+	a := &App{
+		model:       model,
+		keys:        options.Keys,
+		speed:       options.Speed,
+		sessionPath: options.SessionPath,
+		app:         tview.NewApplication(),
+		redexList:   tview.NewList().ShowSecondaryText(false).SetHighlightFullLine(true),
+		history:     tview.NewTextView().SetScrollable(true),
+		status:      tview.NewTextView().SetDynamicColors(true),
+		editor:      tview.NewInputField().SetFieldWidth(0),
+	}
+	a.header = newExprView(a.app)
+	a.tree = newExprView(a.app)
+	if helpKeys := a.keys.Keys(keymap.Help); len(helpKeys) > 0 {
+		a.helpHint = fmt.Sprint(" · ", tview.Escape(helpKeys[0].String()), " help")
+	}
+
+	a.header.SetResizeHandler(func(_ uint) { a.redraw() })
+	a.header.SetClickHandler(a.onClick)
+	a.tree.SetClickHandler(a.onClick)
+	a.tree.SetKeyHandler(a.onTreeKey)
+	a.redexList.SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		if !a.listing && index < len(a.redexes) {
+			a.model.FocusPath(a.redexes[index].Hole.Path())
+			a.redraw()
+		}
+	})
+	a.redexList.SetSelectedFunc(func(index int, _ string, _ string, _ rune) {
+		if index < len(a.redexes) {
+			a.model.FocusPath(a.redexes[index].Hole.Path())
+			a.model.Reduce()
+			a.reduced()
+		}
+	})
+	a.editor.SetDoneFunc(a.onPromptDone)
+
+	a.tree.SetBorder(true).SetTitle(" Tree ")
+	a.redexList.SetBorder(true)
+	a.history.SetBorder(true)
+	a.editor.SetBorder(true)
+	help := helpText(a.keys)
+	helpView := tview.NewTextView().SetDynamicColors(true).SetText(help)
+	helpView.SetBorder(true).SetTitle(" Keys ")
+
+	sidebar := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.redexList, 0, 1, false).
+		AddItem(a.history, 0, 1, false)
+	body := tview.NewFlex().
+		AddItem(a.tree, 0, 2, true).
+		AddItem(sidebar, 0, 1, false)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.header, 1, 0, false).
+		AddItem(body, 0, 1, true).
+		AddItem(a.status, 1, 0, false)
+	helpOverlay := tview.NewGrid().
+		SetColumns(0, 64, 0).
+		SetRows(0, strings.Count(help, "\n")+3, 0).
+		AddItem(helpView, 1, 1, 1, 1, 0, 0, true)
+	editorOverlay := tview.NewGrid().
+		SetColumns(2, 0, 2).
+		SetRows(0, 3, 0).
+		AddItem(a.editor, 1, 1, 1, 1, 0, 0, true)
+	a.pages = tview.NewPages().
+		AddPage("main", layout, true, true).
+		AddPage("help", helpOverlay, true, false).
+		AddPage("edit", editorOverlay, true, false)
+
+	a.app.SetInputCapture(a.onKey)
+	a.reduced()
+	a.app.SetRoot(a.pages, true).SetFocus(a.tree).EnableMouse(true)
+	return a, nil
+}
+
+// Draws on the screen instead of the terminal. Call it before Run.
+func (a *App) SetScreen(screen tcell.Screen) {
+	a.app.SetScreen(screen)
+}
+
+// Returns when the user quits
+func (a *App) Run() error {
+	return a.app.Run()
+}
+
+func (a *App) Model() *Model { return a.model }
+
+func (a *App) saveSession(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return a.model.Session().Write(file)
+}
+
+func (a *App) stop() {
+	a.pause()
+	a.app.Stop()
+	if a.sessionPath != "" {
+		if err := a.saveSession(a.sessionPath); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+		}
+	}
+}
+
+// Redraws for whatever the update changed, and reports whether it handled the action
+func (a *App) update(action keymap.Action) bool {
+	change, err := a.model.Update(action)
+	if err != nil {
+		a.notice = err.Error()
+	}
+	switch change {
+	case Unhandled:
+		return false
+	case Rewritten:
+		a.reduced()
+	default:
+		a.redraw()
+	}
+	return true
+}
+
+func (a *App) pause() {
+	if a.running {
+		a.running = false
+		close(a.stopRun)
+	}
+}
+
+func (a *App) run() {
+	a.running = true
+	a.stopRun = make(chan struct{})
+	done := a.stopRun
+	go func() {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / a.speed))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				a.app.QueueUpdateDraw(func() {
+					if a.stopRun != done || !a.running {
+						return
+					}
+					if !a.model.Step() {
+						a.pause()
+					}
+					a.reduced()
+				})
+			}
+		}
+	}()
+}
+
+func (a *App) changeSpeed(factor float64) {
+	a.speed = min(100, max(0.25, a.speed*factor))
+	if a.running {
+		a.pause()
+		a.run()
+	}
+}
+
+func (a *App) listRedexes() {
+	a.listing = true
+	defer func() { a.listing = false }()
+	current := a.redexList.GetCurrentItem()
+	a.redexList.Clear()
+	a.redexes = a.redexes[:0]
+	for h, e := range walk.ToSeq(walk.Pre(a.model.expr)) {
+		if redex := beta_reduce.AsBetaRedex(e); redex != nil {
+			ctx := h.DisplayContext(ln.EmptyContext().WithDisplayBoundVarAs(ln.DisplayName))
+			notation := ln_pretty.ExprToNotationDoc(e, ctx).String()
+			a.redexList.AddItem(tview.Escape(notation), "", 0, nil)
+			a.redexes = append(a.redexes, beta_reduce.BetaRedex{Hole: h, Lambda: redex.Lambda, Arg: redex.Arg})
+		}
+	}
+	a.redexList.SetCurrentItem(min(current, max(0, len(a.redexes)-1)))
+	a.redexList.SetTitle(fmt.Sprint(" Redexes (", len(a.redexes), ") "))
+}
+
+func (a *App) redraw() {
+	m := a.model
+	path := m.Path()
+	notationLimits := m.limits
+	notationLimits.Expanded = append(slices.Clip(m.limits.Expanded), path)
+	a.header.Clear()
+	ln_pretty.ToNotationDocWith(m.expr, ln.DisplayName, notationLimits).Render(
+		a.header, pretty.RenderOptions{Renderer: renderer, MaxColumns: a.header.Width()},
+	)
+
+	text := m.Focus().ToPrettyDocWith(m.limits, path).PrettyWith(renderer, math.MaxUint)
+	if m.showDiff && m.lastStep != nil {
+		// Its paths are in the previous term, so clicking it must not move the focus
+		diffLimits := m.limits
+		diffLimits.Regions = false
+		text += "\n\nLast step:\n" + m.lastStep.ToPrettyDocWith(diffLimits).PrettyWith(renderer, math.MaxUint)
+	}
+	a.tree.SetText(text)
+
+	a.history.SetText(strings.Join(m.log, "\n")).ScrollToEnd()
+	a.history.SetTitle(fmt.Sprint(" History (", len(m.log)-1, " steps) "))
+
+	if a.notice != "" {
+		a.status.SetText(fmt.Sprint("[red]", tview.Escape(a.notice), "[-]"))
+		return
+	}
+	runState := "paused"
+	if a.running {
+		runState = "running"
+	}
+	a.status.SetText(fmt.Sprintf(
+		"[::b]%s[::-] %s at %g/s · %s · focus %s · depth ≤ %d · nodes ≤ %d%s",
+		m.strategy, runState, a.speed, metrics.Of(m.expr), path, m.limits.MaxDepth, m.limits.MaxNodes, a.helpHint,
+	))
+}
+
+func (a *App) reduced() {
+	a.listRedexes()
+	a.redraw()
+}
+
+func (a *App) onClick(regionID string, double bool) {
+	path, ok := ln_pretty.ParseRegionID(regionID)
+	if !ok {
+		return
+	}
+	a.model.FocusPath(path)
+	if double {
+		a.model.Reduce()
+		a.reduced()
+		return
+	}
+	a.redraw()
+}
+
+func (a *App) onTreeKey(event *tcell.EventKey) bool {
+	switch action := a.keys.Lookup(event); action {
+	case keymap.Edit:
+		a.edit()
+	case keymap.Extract:
+		a.askName("Extract as", "v", rewrite.Extract)
+	case keymap.Abstract:
+		a.askName("Abstract as", "v", rewrite.Abstract)
+	case keymap.Rename:
+		if lambda, ok := a.model.Focus().Expr.(ln.Lambda); ok {
+			a.askName("Rename to", lambda.ArgName(), rewrite.Rename)
+		} else {
+			a.notice = "Only lambdas can be renamed"
+			a.redraw()
+		}
+	default:
+		return !action.Global() && a.update(action)
+	}
+	return true
+}
+
+func (a *App) prompt(title string, text string, onEnter func(text string) error) {
+	a.onPrompt = onEnter
+	a.editor.SetText(text)
+	a.editor.SetTitle(fmt.Sprint(" ", title, " "))
+	a.pages.ShowPage("edit")
+	a.app.SetFocus(a.editor)
+}
+
+func (a *App) onPromptDone(key tcell.Key) {
+	switch key {
+	case tcell.KeyEnter:
+		if err := a.onPrompt(a.editor.GetText()); err != nil {
+			a.editor.SetTitle(fmt.Sprint(" ", tview.Escape(err.Error()), " "))
+			return
+		}
+	case tcell.KeyEscape:
+	default:
+		return
+	}
+	a.pages.HidePage("edit")
+	a.app.SetFocus(a.tree)
+	a.reduced()
+}
+
+// The subterm is shown and parsed with the names bound around it
+func (a *App) edit() {
+	focus := a.model.Focus()
+	// Binders are shown renamed away from free variables, so that the text parses back the same
+	ctx := focus.Hole.DisplayContext(ln.EmptyContext().
+		WithDisplayBoundVarAs(ln.DisplayName).
+		WithReserved(metrics.Of(a.model.Expr()).FreeVars...))
+	scope := ctx.BoundNames()
+	a.prompt(fmt.Sprint("Edit ", a.model.Path()), ln_pretty.ExprToNotationDoc(focus.Expr, ctx).String(), func(text string) error {
+		parsed, err := parser.Parse(tokenizer.New(strings.NewReader(text)))
+		if err != nil {
+			return err
+		}
+		e := parse_tree_to_locally_nameless.ToLocallyNamelessIn(*parsed, scope)
+		return a.model.Rewrite(func(focus walk.Focus) (ln.Expr, ln.Path, error) {
+			return focus.Hole.Fill(e), focus.Hole.Path(), nil
+		})
+	})
+}
+
+func (a *App) askName(title string, name string, toRewrite func(name string) rewrite.Rewrite) {
+	a.prompt(title, name, func(text string) error {
+		parsed, err := parser.Parse(tokenizer.New(strings.NewReader(text)))
+		if err != nil {
+			return err
+		}
+		variable, ok := parsed.Item.(parse_tree.Var)
+		if !ok {
+			return errors.New(fmt.Sprint("Expected a name, got ", text))
+		}
+		return a.model.Rewrite(toRewrite(variable.Name))
+	})
+}
+
+func (a *App) cyclePanes(change int) {
+	panes := []tview.Primitive{a.tree, a.redexList, a.history}
+	for i, pane := range panes {
+		if pane.HasFocus() {
+			a.app.SetFocus(panes[(i+change+len(panes))%len(panes)])
+			return
+		}
+	}
+	a.app.SetFocus(a.tree)
+}
+
+func (a *App) save() {
+	if a.sessionPath != "" {
+		if err := a.saveSession(a.sessionPath); err != nil {
+			a.notice = err.Error()
+		} else {
+			a.notice = fmt.Sprint("Saved to ", a.sessionPath)
+		}
+		a.redraw()
+		return
+	}
+	a.prompt("Save session to", "session.json", func(path string) error {
+		if err := a.saveSession(path); err != nil {
+			return err
+		}
+		a.sessionPath = path
+		a.notice = fmt.Sprint("Saved to ", a.sessionPath, ", and will be saved again on quit")
+		return nil
+	})
+}
+
+func (a *App) onKey(event *tcell.EventKey) *tcell.EventKey {
+	if name, _ := a.pages.GetFrontPage(); name == "edit" {
+		return event
+	}
+	if a.notice != "" {
+		a.notice = ""
+		a.redraw()
+	}
+	action := a.keys.Lookup(event)
+	if a.running && action != keymap.Faster && action != keymap.Slower {
+		a.pause()
+		a.redraw()
+		return nil
+	}
+	if name, _ := a.pages.GetFrontPage(); name == "help" {
+		if action == keymap.Help || action == keymap.Quit || event.Key() == tcell.KeyESC {
+			a.pages.HidePage("help")
+		}
+		return nil
+	}
+	switch action {
+	case keymap.Quit:
+		a.stop()
+	case keymap.Save:
+		a.save()
+	case keymap.NextPane:
+		a.cyclePanes(1)
+	case keymap.PreviousPane:
+		a.cyclePanes(-1)
+	case keymap.Help:
+		a.pages.ShowPage("help")
+	case keymap.Run:
+		a.run()
+		a.redraw()
+	case keymap.Faster:
+		a.changeSpeed(2)
+		a.redraw()
+	case keymap.Slower:
+		a.changeSpeed(0.5)
+		a.redraw()
+	default:
+		if !action.Global() || !a.update(action) {
+			return event
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/gusbicalho/go-lambda/keymap"
+	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/session"
+	"github.com/rivo/tview"
+)
+
+type harness struct {
+	t      *testing.T
+	app    *App
+	screen tcell.SimulationScreen
+	done   chan error
+}
+
+// Runs the app on a simulated screen until the test ends
+func start(t *testing.T, source string) *harness {
+	saved, err := session.New(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := keymap.Preset("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, err := New(saved, Options{Keys: keys, Speed: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen := tcell.NewSimulationScreen("UTF-8")
+	app.SetScreen(screen)
+	screen.SetSize(100, 30)
+	h := &harness{t, app, screen, make(chan error, 1)}
+	go func() { h.done <- app.Run() }()
+	t.Cleanup(func() {
+		app.app.Stop()
+		<-h.done
+	})
+	return h
+}
+
+// Read on the app's goroutine, which is the one that draws
+func (h *harness) screenText() string {
+	builder := strings.Builder{}
+	h.app.app.QueueUpdate(func() {
+		cells, width, _ := h.screen.GetContents()
+		for i, cell := range cells {
+			if len(cell.Runes) > 0 {
+				builder.WriteRune(cell.Runes[0])
+			}
+			if (i+1)%width == 0 {
+				builder.WriteRune('\n')
+			}
+		}
+	})
+	return builder.String()
+}
+
+// Waits for the screen to show every text
+func (h *harness) expect(texts ...string) {
+	h.t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		screen := h.screenText()
+		missing := ""
+		for _, text := range texts {
+			if !strings.Contains(screen, text) {
+				missing = text
+				break
+			}
+		}
+		if missing == "" {
+			return
+		}
+		if time.Now().After(deadline) {
+			h.t.Fatalf("Expected the screen to show %q:\n%s", missing, screen)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (h *harness) press(key tcell.Key) {
+	h.screen.InjectKey(key, 0, tcell.ModNone)
+}
+
+func (h *harness) typeText(text string) {
+	for _, r := range text {
+		h.screen.InjectKey(tcell.KeyRune, r, tcell.ModNone)
+	}
+}
+
+func TestNavigateAndReduce(t *testing.T) {
+	h := start(t, "(\\x. x) ((\\y. y) z)")
+	h.expect("Redexes (2)", "History (0 steps)", "normal paused", "focus /")
+	h.press(tcell.KeyRight)
+	h.press(tcell.KeyDown)
+	h.expect("focus /1")
+	h.press(tcell.KeyEnter)
+	h.expect("Redexes (1)", "History (1 steps)", "(\\x. x) z", "Last step:")
+	h.typeText("u")
+	h.expect("Redexes (2)", "History (0 steps)")
+}
+
+func TestRun(t *testing.T) {
+	h := start(t, "(\\x. \\y. x y) (\\z. z) w")
+	h.typeText(" ")
+	h.expect("Redexes (0)", "History (3 steps)", "paused")
+}
+
+func TestHelpAndPrompts(t *testing.T) {
+	h := start(t, "\\x. f x")
+	h.typeText("?")
+	h.expect("Keys", "reduce the focused redex")
+	h.press(tcell.KeyEsc)
+	h.typeText("c")
+	h.expect("Rename to")
+	h.press(tcell.KeyBackspace2)
+	h.typeText("y")
+	h.press(tcell.KeyEnter)
+	h.expect("History (1 steps)", "\\y. f y")
+	h.typeText("X")
+	h.expect("Extract as")
+	h.press(tcell.KeyBackspace2)
+	h.typeText("(")
+	h.press(tcell.KeyEnter)
+	h.expect("Unexpected token")
+	h.press(tcell.KeyEsc)
+	h.press(tcell.KeyRight)
+	h.typeText("c")
+	h.expect("Only lambdas can be renamed")
+}
+
+// Where the text first shows on the screen
+func (h *harness) find(text string) (x int, y int) {
+	h.t.Helper()
+	h.expect(text)
+	for y, line := range strings.Split(h.screenText(), "\n") {
+		if before, _, found := strings.Cut(line, text); found {
+			return len([]rune(before)), y
+		}
+	}
+	h.t.Fatalf("Expected the screen to show %q", text)
+	return 0, 0
+}
+
+// Clicks the last cell of the text, then waits so that the next click
+// is not taken for the second half of a double click, with some slack
+// for the app to get to the events
+func (h *harness) click(text string) {
+	x, y := h.find(text)
+	x += len([]rune(text)) - 1
+	h.screen.InjectMouse(x, y, tcell.Button1, tcell.ModNone)
+	h.screen.InjectMouse(x, y, tcell.ButtonNone, tcell.ModNone)
+	time.Sleep(tview.DoubleClickInterval + 200*time.Millisecond)
+}
+
+func TestClickToFocus(t *testing.T) {
+	h := start(t, "(\\x. x) ((\\y. y) z)")
+	h.click("└► z")
+	h.expect("focus /1/1 ")
+	h.click("0:x")
+	h.expect("focus /0/0 ")
+}
+
+// Double clicks the first cell of the text
+func (h *harness) doubleClick(text string) {
+	x, y := h.find(text)
+	for range 2 {
+		h.screen.InjectMouse(x, y, tcell.Button1, tcell.ModNone)
+		h.screen.InjectMouse(x, y, tcell.ButtonNone, tcell.ModNone)
+	}
+}
+
+func TestDoubleClickToReduce(t *testing.T) {
+	h := start(t, "(\\x. x) ((\\y. y) z)")
+	// The arrow to the argument belongs to the application
+	h.doubleClick("└► z")
+	h.expect("Redexes (1)", "History (1 steps)", "(\\x. x) z", "focus /1 ")
+}
+
+func TestEditUnchanged(t *testing.T) {
+	h := start(t, "a")
+	// Typed in, the binder keeps its name, so reducing gives \y. y with y free
+	h.typeText("i")
+	h.press(tcell.KeyBackspace2)
+	h.typeText("(\\x. \\y. x) y")
+	h.press(tcell.KeyEnter)
+	h.expect("History (1 steps)")
+	h.press(tcell.KeyEnter)
+	h.expect("History (2 steps)")
+	h.press(tcell.KeyRight)
+	h.expect("focus /0 ")
+	deBruijn := func() (notation string) {
+		h.app.app.QueueUpdate(func() {
+			notation = ln.ToLambdaNotation(h.app.Model().Expr(), ln.DisplayDeBruijn)
+		})
+		return notation
+	}
+	before := deBruijn()
+	// The free y is not shown as the name of the binder around it
+	h.typeText("i")
+	h.expect("Edit /0")
+	h.press(tcell.KeyEnter)
+	h.expect("History (3 steps)")
+	if after := deBruijn(); after != before {
+		t.Errorf("Expected the term to stay %s, got %s", before, after)
+	}
+}
@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"math"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+type exprView struct {
+	*tview.TextView
+	onKey    func(*tcell.EventKey) bool
+	onResize func(width uint)
+	onClick  func(regionID string, double bool)
+	width    int
+	// Region of the last click, since double clicks do not highlight regions
+	clicked string
+}
+
+func newExprView(app *tview.Application) *exprView {
+	return &exprView{
+		tview.NewTextView().
+			SetDynamicColors(true).
+			SetRegions(true).
+			SetChangedFunc(
+				func() {
+					app.Draw()
+				},
+			),
+		nil,
+		nil,
+		nil,
+		0,
+		"",
+	}
+}
+
+// Inner width of the view, unbounded until it is first drawn
+func (t *exprView) Width() uint {
+	if t.width <= 0 {
+		return math.MaxUint
+	}
+	return uint(t.width)
+}
+
+func (t *exprView) SetResizeHandler(onResize func(width uint)) {
+	t.onResize = onResize
+}
+
+func (t *exprView) Draw(screen tcell.Screen) {
+	if _, _, width, _ := t.GetInnerRect(); width != t.width {
+		t.width = width
+		if t.onResize != nil {
+			t.onResize(t.Width())
+		}
+	}
+	t.TextView.Draw(screen)
+}
+
+func (t *exprView) SetKeyHandler(onKey func(event *tcell.EventKey) bool) {
+	t.onKey = onKey
+}
+
+func (t *exprView) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	super := t.TextView.InputHandler()
+	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		if t.onKey != nil && event != nil && t.onKey(event) {
+			return
+		}
+		super(event, setFocus)
+	})
+}
+
+// Called with the region under the pointer, when regions are on
+func (t *exprView) SetClickHandler(onClick func(regionID string, double bool)) {
+	t.onClick = onClick
+}
+
+func (t *exprView) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+	super := t.TextView.MouseHandler()
+	return t.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+		consumed, capture = super(action, event, setFocus)
+		if t.onClick == nil {
+			return consumed, capture
+		}
+		switch action {
+		case tview.MouseLeftClick:
+			// The text view highlights the region clicked, which we only use to find it
+			t.clicked = ""
+			if highlights := t.GetHighlights(); len(highlights) > 0 {
+				t.clicked = highlights[0]
+				t.Highlight()
+				t.onClick(t.clicked, false)
+			}
+		case tview.MouseLeftDoubleClick:
+			if t.clicked != "" {
+				t.onClick(t.clicked, true)
+			}
+		}
+		return consumed, capture
+	})
+}
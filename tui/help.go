@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gusbicalho/go-lambda/keymap"
+	"github.com/rivo/tview"
+)
+
+// Lists the keys bound to each action, tree actions first
+func helpText(keys keymap.Keymap) string {
+	builder := strings.Builder{}
+	section := func(title string, global bool) {
+		fmt.Fprintf(&builder, "[::b]%s[::-]\n", title)
+		for action := keymap.Parent; action <= keymap.Quit; action++ {
+			bound := keys.Keys(action)
+			if action.Global() != global || len(bound) == 0 {
+				continue
+			}
+			names := make([]string, len(bound))
+			for i, key := range bound {
+				names[i] = key.String()
+			}
+			fmt.Fprintf(&builder, "  %-16s %s\n", tview.Escape(strings.Join(names, " ")), action.Description())
+		}
+	}
+	section("Tree", false)
+	builder.WriteString("  click            focus a subterm\n")
+	builder.WriteString("  double-click     reduce a subterm\n\n")
+	section("Anywhere", true)
+	return strings.TrimSuffix(builder.String(), "\n")
+}
@@ -0,0 +1,311 @@
+package tui
+
+import (
+	"slices"
+
+	"github.com/gusbicalho/go-lambda/keymap"
+	"github.com/gusbicalho/go-lambda/locally_nameless/beta_reduce"
+	"github.com/gusbicalho/go-lambda/locally_nameless/diff"
+	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	ln_pretty "github.com/gusbicalho/go-lambda/locally_nameless/pretty"
+	"github.com/gusbicalho/go-lambda/locally_nameless/rewrite"
+	"github.com/gusbicalho/go-lambda/locally_nameless/walk"
+	"github.com/gusbicalho/go-lambda/session"
+)
+
+// What the navigator shows and works on, independent of the widgets that draw it
+type Model struct {
+	saved    session.Session
+	expr     ln.Expr
+	nav      walk.Nav
+	log      []string
+	limits   ln_pretty.Options
+	strategy beta_reduce.Strategy
+	lastStep *diff.Diff
+	showDiff bool
+	undo     []snapshot
+}
+
+type snapshot struct {
+	expr      ln.Expr
+	path      ln.Path
+	lastStep  *diff.Diff
+	logLength int
+}
+
+// What an update changed, so the view knows what to draw again
+type Change uint
+
+const (
+	// The action is not the model's to handle
+	Unhandled Change = iota
+	// The focus or how the term is shown
+	Moved
+	// The term itself, so its redexes must be listed again
+	Rewritten
+)
+
+// Resumes the saved session, with undo back through its history
+func NewModel(saved session.Session) (*Model, error) {
+	terms, err := saved.Terms()
+	if err != nil {
+		return nil, err
+	}
+	strategy, err := beta_reduce.ParseStrategy(saved.Strategy)
+	if err != nil {
+		return nil, err
+	}
+	m := &Model{
+		saved:    saved,
+		expr:     terms[len(terms)-1],
+		log:      slices.Clone(saved.History),
+		limits:   ln_pretty.Options{Regions: true},
+		strategy: strategy,
+		showDiff: true,
+	}
+	m.nav = walk.ToNav(m.expr)
+	if path, err := ln.ParsePath(saved.Focus); err == nil {
+		m.FocusPath(path)
+	}
+	m.setDepth(defaultMaxDepth)
+	if saved.MaxDepth > 0 {
+		m.setDepth(saved.MaxDepth)
+	}
+	for i, term := range terms[:len(terms)-1] {
+		m.undo = append(m.undo, snapshot{term, ln.Path{}, nil, i + 1})
+	}
+	return m, nil
+}
+
+func (m *Model) Expr() ln.Expr                  { return m.expr }
+func (m *Model) Path() ln.Path                  { return m.nav.Path() }
+func (m *Model) Focus() walk.Focus              { return m.nav.Focus() }
+func (m *Model) Strategy() beta_reduce.Strategy { return m.strategy }
+
+// Every term so far, ending with the current one
+func (m *Model) Log() []string { return m.log }
+
+func (m *Model) Session() session.Session {
+	current := m.saved
+	current.Term, current.History = m.log[len(m.log)-1], slices.Clone(m.log)
+	current.Focus, current.Strategy, current.MaxDepth = m.Path().String(), m.strategy.String(), m.limits.MaxDepth
+	return current
+}
+
+// Applies an action on the term or the focus. Actions on the widgets are Unhandled.
+func (m *Model) Update(action keymap.Action) (Change, error) {
+	switch action {
+	case keymap.Parent:
+		m.parent()
+	case keymap.FirstChild:
+		m.firstChild()
+	case keymap.PreviousSubterm:
+		m.previousSubterm()
+	case keymap.NextSubterm:
+		m.nextSubterm()
+	case keymap.NextRedex:
+		m.jumpRedex(true)
+	case keymap.PreviousRedex:
+		m.jumpRedex(false)
+	case keymap.Expand:
+		m.toggleExpanded()
+	case keymap.MoreDepth:
+		m.changeDepth(2)
+	case keymap.LessDepth:
+		m.changeDepth(-2)
+	case keymap.ToggleDiff:
+		m.showDiff = !m.showDiff
+	case keymap.SwitchStrategy:
+		next := slices.Index(beta_reduce.Strategies, m.strategy) + 1
+		m.strategy = beta_reduce.Strategies[next%len(beta_reduce.Strategies)]
+	case keymap.Reduce:
+		m.Reduce()
+		return Rewritten, nil
+	case keymap.Step:
+		m.Step()
+		return Rewritten, nil
+	case keymap.ReduceAll:
+		m.reduceAll()
+		return Rewritten, nil
+	case keymap.Undo:
+		m.undoStep()
+		return Rewritten, nil
+	case keymap.Inline:
+		return Rewritten, m.Rewrite(rewrite.Inline())
+	default:
+		return Unhandled, nil
+	}
+	return Moved, nil
+}
+
+func (m *Model) FocusPath(path ln.Path) {
+	if focused := walk.NavTo(m.expr, path); focused != nil {
+		m.nav = *focused
+	}
+}
+
+// Contracts the focused redex, if the focus is one
+func (m *Model) Reduce() bool {
+	before := m.snapshot()
+	var updated bool
+	m.nav, updated = m.nav.UpdateExpr(func(e ln.Expr) *ln.Expr {
+		redex := beta_reduce.AsBetaRedex(e)
+		if redex == nil {
+			return nil
+		}
+		step := diff.OfStep(beta_reduce.BetaRedex{Hole: m.nav.Focus().Hole, Lambda: redex.Lambda, Arg: redex.Arg})
+		m.lastStep = &step
+		e = redex.Reduce()
+		return &e
+	})
+	if updated {
+		m.expr = m.nav.Focus().Realize()
+		m.log = append(m.log, session.Notation(m.expr))
+		// Paths may point somewhere else in the new term
+		m.limits.Expanded = nil
+		m.undo = append(m.undo, before)
+	}
+	return updated
+}
+
+// Contracts the redex the strategy picks, false when in normal form
+func (m *Model) Step() bool {
+	redex := m.strategy.Next(m.expr)
+	if redex != nil {
+		m.FocusPath(redex.Hole.Path())
+		m.Reduce()
+	}
+	return redex != nil
+}
+
+func (m *Model) Rewrite(r rewrite.Rewrite) error {
+	before := m.snapshot()
+	rewritten, path, err := r(m.nav.Focus())
+	if err != nil {
+		return err
+	}
+	m.lastStep = diff.Of(m.expr, rewritten)
+	m.expr = rewritten
+	m.nav = walk.ToNav(m.expr)
+	m.FocusPath(path)
+	m.log = append(m.log, session.Notation(m.expr))
+	m.limits.Expanded = nil
+	m.undo = append(m.undo, before)
+	return nil
+}
+
+func (m *Model) snapshot() snapshot {
+	return snapshot{m.expr, m.nav.Path(), m.lastStep, len(m.log)}
+}
+
+func (m *Model) reduceAll() {
+	// Undone all at once
+	undoLength := len(m.undo)
+	for steps := 0; steps < 1000; steps++ {
+		if !m.Step() {
+			break
+		}
+	}
+	if len(m.undo) > undoLength {
+		m.undo = m.undo[:undoLength+1]
+	}
+}
+
+func (m *Model) undoStep() {
+	if len(m.undo) == 0 {
+		return
+	}
+	last := m.undo[len(m.undo)-1]
+	m.undo = m.undo[:len(m.undo)-1]
+	m.expr, m.lastStep, m.log = last.expr, last.lastStep, m.log[:last.logLength]
+	m.nav = walk.ToNav(m.expr)
+	m.FocusPath(last.path)
+	m.limits.Expanded = nil
+}
+
+// Wraps around at either end
+func (m *Model) jumpRedex(forward bool) {
+	before, after := m.strategy.Around(m.expr, m.nav.Path())
+	target := after
+	if !forward {
+		target = before
+	}
+	if target == nil {
+		for redex := range m.strategy.Redexes(m.expr) {
+			target = &redex
+			if forward {
+				break
+			}
+		}
+	}
+	if target != nil {
+		m.FocusPath(target.Hole.Path())
+	}
+}
+
+func (m *Model) toggleExpanded() {
+	path := m.nav.Path()
+	for i, expanded := range m.limits.Expanded {
+		if slices.Equal(expanded, path) {
+			m.limits.Expanded = slices.Delete(m.limits.Expanded, i, i+1)
+			return
+		}
+	}
+	m.limits.Expanded = append(m.limits.Expanded, path)
+}
+
+func (m *Model) changeDepth(change int) {
+	m.setDepth(uint(max(1, int(m.limits.MaxDepth)+change)))
+}
+
+const defaultMaxDepth = 12
+
+// Node limits follow the depth, whether it is the default, restored or changed
+func (m *Model) setDepth(depth uint) {
+	m.limits.MaxDepth = depth
+	m.limits.MaxNodes = 40 * depth
+}
+
+func (m *Model) parent() {
+	if parent, _ := m.nav.Parent(); parent != nil {
+		m.nav = *parent
+	}
+}
+
+func (m *Model) firstChild() {
+	if child := m.nav.Child(0); child != nil {
+		m.nav = *child
+	}
+}
+
+func (m *Model) nextSubterm() {
+	n := &m.nav
+	for {
+		parent, index := n.Parent()
+		if parent == nil {
+			break
+		}
+		if sibling := parent.Child(index + 1); sibling != nil {
+			m.nav = *sibling
+			break
+		}
+		n = parent
+	}
+}
+
+func (m *Model) previousSubterm() {
+	parent, index := m.nav.Parent()
+	if parent == nil {
+		return
+	}
+	if index > 0 {
+		if sibling := parent.Child(index - 1); sibling != nil {
+			m.nav = *sibling
+			if child := m.nav.Child(m.nav.Children() - 1); child != nil {
+				m.nav = *child
+			}
+			return
+		}
+	}
+	m.nav = *parent
+}
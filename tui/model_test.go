@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/gusbicalho/go-lambda/keymap"
+	ln "github.com/gusbicalho/go-lambda/locally_nameless/expr"
+	"github.com/gusbicalho/go-lambda/session"
+)
+
+func newModel(t *testing.T, source string) *Model {
+	saved, err := session.New(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewModel(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestUpdate(t *testing.T) {
+	cases := []struct {
+		testName string
+		source   string
+		actions  []keymap.Action
+		path     string
+		term     string
+	}{
+		{"navigate", "\\x. f x (g x)", []keymap.Action{keymap.FirstChild, keymap.FirstChild, keymap.NextSubterm}, "/0/1", "\\x. f x (g x)"},
+		{"previous goes into the last child", "f (a b) c", []keymap.Action{keymap.FirstChild, keymap.NextSubterm, keymap.PreviousSubterm}, "/0/1", "f (a b) c"},
+		{"parent", "f a", []keymap.Action{keymap.FirstChild, keymap.Parent, keymap.Parent}, "/", "f a"},
+		{"reduce the focus", "(\\x. x) ((\\y. y) z)", []keymap.Action{keymap.Reduce}, "/", "(\\y. y) z"},
+		{"reduce a non-redex", "f ((\\y. y) z)", []keymap.Action{keymap.Reduce}, "/", "f ((\\y. y) z)"},
+		{"jump to a redex", "f ((\\y. y) z)", []keymap.Action{keymap.NextRedex, keymap.Reduce}, "/1", "f z"},
+		{"applicative order", "(\\x. x) ((\\y. y) z)", []keymap.Action{keymap.SwitchStrategy, keymap.Step}, "/1", "(\\x. x) z"},
+		{"switching wraps around to normal order", "(\\x. x) ((\\y. y) z)", []keymap.Action{keymap.SwitchStrategy, keymap.SwitchStrategy, keymap.Step}, "/", "(\\y. y) z"},
+		{"reduce all", "(\\x. \\y. x y) (\\z. z) w", []keymap.Action{keymap.ReduceAll}, "/", "w"},
+		{"reduce all is undone at once", "(\\x. \\y. x y) (\\z. z) w", []keymap.Action{keymap.ReduceAll, keymap.Undo}, "/0", "(\\x. \\y. x y) (\\z. z) w"},
+		{"inline", "(\\x. f x) a", []keymap.Action{keymap.Inline}, "/", "f a"},
+	}
+	for _, c := range cases {
+		m := newModel(t, c.source)
+		for _, action := range c.actions {
+			if change, err := m.Update(action); change == Unhandled || err != nil {
+				t.Fatalf("%s - %s: %v, %v", c.testName, action, change, err)
+			}
+		}
+		if path := m.Path().String(); path != c.path {
+			t.Errorf("%s - Expected focus %s, Actual: %s", c.testName, c.path, path)
+		}
+		if term := ln.ToLambdaNotation(m.Expr(), ln.DisplayName); term != c.term {
+			t.Errorf("%s - Expected: %s, Actual: %s", c.testName, c.term, term)
+		}
+	}
+}
+
+func TestSession(t *testing.T) {
+	m := newModel(t, "(\\x. x) ((\\y. y) z)")
+	m.Update(keymap.Step)
+	m.Update(keymap.Step)
+	m.Update(keymap.Undo)
+	if change, _ := m.Update(keymap.Quit); change != Unhandled {
+		t.Errorf("Expected quitting to be left to the app, got %v", change)
+	}
+	saved := m.Session()
+	if len(saved.History) != 2 || saved.Term != saved.History[1] {
+		t.Fatalf("Expected two terms, ending with the current one, got %v", saved.History)
+	}
+
+	resumed, err := NewModel(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.limits.MaxDepth != m.limits.MaxDepth || resumed.limits.MaxNodes != m.limits.MaxNodes {
+		t.Errorf("Expected the limits of the saved session, %+v, got %+v", m.limits, resumed.limits)
+	}
+	resumed.Update(keymap.Undo)
+	if term := ln.ToLambdaNotation(resumed.Expr(), ln.DisplayName); term != "(\\x. x) ((\\y. y) z)" {
+		t.Errorf("Expected to undo back to the source, got %s", term)
+	}
+}